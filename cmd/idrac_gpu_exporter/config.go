@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+	"maps"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -25,26 +27,46 @@ func ReloadConfig(filename string) {
 
 	cfg.FromEnvironment()
 	err := cfg.Validate()
+	if err == nil {
+		err = checkLabels(cfg)
+	}
 	if err != nil {
 		log.Error("Invalid configuration: %v", err)
 		return
 	}
 
+	// The collectors are reset after the configuration is unlocked, since
+	// resetting a collector removes its event subscription from the BMC
+	reset := []string{}
 	old.Mutex.Lock()
-	defer old.Mutex.Unlock()
-
 	for k, v := range cfg.Hosts {
 		h, ok := old.Hosts[k]
 		if ok {
-			if h.Username != v.Username || h.Password != v.Password || h.Scheme != v.Scheme {
+			if h.Implicit || h.Username != v.Username || h.Password != v.Password || h.Scheme != v.Scheme || !maps.Equal(h.Labels, v.Labels) {
 				old.Hosts[k] = v
-				collector.Reset(k)
+				reset = append(reset, k)
 			}
 		} else {
 			old.Hosts[k] = v
 		}
 	}
 
+	// Hosts removed from the configuration, and the hosts added on demand
+	// when the default host was removed, are no longer scraped
+	_, hasDefault := cfg.Hosts["default"]
+	for k, h := range old.Hosts {
+		if _, ok := cfg.Hosts[k]; ok || (h.Implicit && hasDefault) {
+			continue
+		}
+		delete(old.Hosts, k)
+		reset = append(reset, k)
+	}
+	old.Mutex.Unlock()
+
+	for _, k := range reset {
+		collector.Reset(k)
+	}
+
 	log.Info("Configuration reload was successful")
 }
 
@@ -106,6 +128,20 @@ func WatchConfig(filename string) {
 	}
 }
 
+// checkLabels verifies that the labels of the hosts do not collide with the
+// labels of the metrics
+func checkLabels(cfg *config.RootConfig) error {
+	for k, v := range cfg.Hosts {
+		if len(v.Labels) == 0 {
+			continue
+		}
+		if err := collector.CheckLabels(v.Labels); err != nil {
+			return fmt.Errorf("invalid labels for host %s: %v", k, err)
+		}
+	}
+	return nil
+}
+
 func LoadConfig(filename string) {
 	cfg := config.NewConfig()
 
@@ -119,6 +155,9 @@ func LoadConfig(filename string) {
 
 	cfg.FromEnvironment()
 	err := cfg.Validate()
+	if err == nil {
+		err = checkLabels(cfg)
+	}
 	if err != nil {
 		log.Fatal("Invalid configuration: %v", err)
 	}
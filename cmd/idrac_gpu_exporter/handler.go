@@ -2,6 +2,8 @@ package main
 
 import (
 	"compress/gzip"
+	"crypto/subtle"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +11,7 @@ import (
 	"sync"
 
 	"github.com/smc-public/idrac_gpu_exporter/internal/collector"
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
 	"github.com/smc-public/idrac_gpu_exporter/internal/log"
 	"github.com/smc-public/idrac_gpu_exporter/internal/version"
 )
@@ -30,7 +33,13 @@ const landingPageTemplate = `<html lang="en">
 <body style="font-family: sans-serif">
 <h2>iDRAC GPU Exporter</h2>
 <div>Build information: version=%s revision=%s</div>
-<ul><li><a href="/metrics">Metrics</a> (needs <code>target</code> parameter)</li></ul>
+<ul>
+<li><a href="/metrics">Metrics</a> (needs <code>target</code> parameter)</li>
+<li><a href="/metrics/all">Metrics for all configured hosts</a></li>
+<li><a href="/sd">Prometheus HTTP service discovery</a></li>
+<li><a href="/inventory">GPU inventory</a> (needs <code>target</code> parameter)</li>
+<li><a href="/events">Recent GPU events</a> (needs <code>target</code> parameter)</li>
+</ul>
 </body>
 </html>
 `
@@ -48,6 +57,111 @@ func healthHandler(rsp http.ResponseWriter, req *http.Request) {
 	// just return a simple 200 for now
 }
 
+// sdLabelPrefix is the prefix of the meta labels returned for the labels of
+// the hosts by the service discovery endpoint
+const sdLabelPrefix = "__meta_idrac_label_"
+
+// sdTargetGroup is a target group in the format of the Prometheus http_sd_config
+type sdTargetGroup struct {
+	Targets []string          `json:"targets"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+func sdHandler(rsp http.ResponseWriter, req *http.Request) {
+	groups := []sdTargetGroup{}
+
+	for _, target := range config.GetTargets() {
+		host := config.GetHostConfig(target)
+		if host == nil {
+			continue
+		}
+
+		// The labels of the host are already added to its metrics, so they
+		// are only returned as meta labels, which can be used for relabeling
+		var labels map[string]string
+		if len(host.Labels) > 0 {
+			labels = make(map[string]string, len(host.Labels))
+			for k, v := range host.Labels {
+				labels[sdLabelPrefix+k] = v
+			}
+		}
+
+		groups = append(groups, sdTargetGroup{
+			Targets: []string{target},
+			Labels:  labels,
+		})
+	}
+
+	log.Debug("Handling service discovery request from %s (%d hosts)", req.Host, len(groups))
+
+	rsp.Header().Set(contentTypeHeader, "application/json")
+	err := json.NewEncoder(rsp).Encode(groups)
+	if err != nil {
+		log.Error("Error writing service discovery response to client %s: %v", req.Host, err)
+	}
+}
+
+func inventoryHandler(rsp http.ResponseWriter, req *http.Request) {
+	target := req.URL.Query().Get("target")
+	if target == "" {
+		log.Error("Received request from %s without 'target' parameter", req.Host)
+		http.Error(rsp, "Query parameter 'target' is mandatory", http.StatusBadRequest)
+		return
+	}
+
+	log.Debug("Handling inventory request from %s for host %s", req.Host, target)
+
+	rsp.Header().Set(contentTypeHeader, "application/json")
+	err := json.NewEncoder(rsp).Encode(collector.GetInventory(target))
+	if err != nil {
+		log.Error("Error writing inventory to client %s: %v", req.Host, err)
+	}
+}
+
+func eventsHandler(rsp http.ResponseWriter, req *http.Request) {
+	target := req.URL.Query().Get("target")
+	if target == "" {
+		log.Error("Received request from %s without 'target' parameter", req.Host)
+		http.Error(rsp, "Query parameter 'target' is mandatory", http.StatusBadRequest)
+		return
+	}
+
+	log.Debug("Handling events request from %s for host %s", req.Host, target)
+
+	rsp.Header().Set(contentTypeHeader, "application/json")
+	err := json.NewEncoder(rsp).Encode(collector.GetEvents(target))
+	if err != nil {
+		log.Error("Error writing events to client %s: %v", req.Host, err)
+	}
+}
+
+// pushHandler receives the events pushed by the event services the exporter
+// subscribed to, which pass the push token in the URL
+func pushHandler(rsp http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rsp, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := config.Config.Events.PushToken
+	if token == "" || subtle.ConstantTimeCompare([]byte(req.URL.Query().Get("token")), []byte(token)) != 1 {
+		log.Error("Received pushed event from %s with invalid token", req.RemoteAddr)
+		http.Error(rsp, "Invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	data, err := io.ReadAll(io.LimitReader(req.Body, 4*1024*1024))
+	if err != nil {
+		log.Error("Error reading pushed event from %s: %v", req.RemoteAddr, err)
+		http.Error(rsp, "Error reading event", http.StatusBadRequest)
+		return
+	}
+
+	if !collector.PushEvent(data) {
+		log.Debug("Ignoring pushed event from %s for unknown host", req.RemoteAddr)
+	}
+}
+
 func resetHandler(rsp http.ResponseWriter, req *http.Request) {
 	target := req.URL.Query().Get("target")
 	if target == "" {
@@ -63,13 +177,19 @@ func resetHandler(rsp http.ResponseWriter, req *http.Request) {
 
 func metricsHandler(rsp http.ResponseWriter, req *http.Request) {
 	// Config is reloaded in the background watcher, just use current config
-	target := req.URL.Query().Get("target")
-	if target == "" {
+	targets := uniqueTargets(req.URL.Query()["target"])
+	if len(targets) == 0 {
 		log.Error("Received request from %s without 'target' parameter", req.Host)
 		http.Error(rsp, "Query parameter 'target' is mandatory", http.StatusBadRequest)
 		return
 	}
 
+	if len(targets) > 1 {
+		fanoutMetrics(rsp, req, targets)
+		return
+	}
+
+	target := targets[0]
 	log.Debug("Handling request from %s for host %s", req.Host, target)
 
 	c, err := collector.GetCollector(target)
@@ -92,6 +212,39 @@ func metricsHandler(rsp http.ResponseWriter, req *http.Request) {
 
 	log.Debug("Metrics for host %s collected", target)
 
+	writeMetrics(rsp, req, metrics)
+}
+
+func allMetricsHandler(rsp http.ResponseWriter, req *http.Request) {
+	targets := config.GetTargets()
+	if len(targets) == 0 {
+		log.Error("Received request from %s for all hosts, but no hosts are configured", req.Host)
+		http.Error(rsp, "No hosts are configured", http.StatusNotFound)
+		return
+	}
+
+	fanoutMetrics(rsp, req, targets)
+}
+
+func fanoutMetrics(rsp http.ResponseWriter, req *http.Request, targets []string) {
+	log.Debug("Handling request from %s for %d hosts", req.Host, len(targets))
+
+	metrics, err := collector.GatherTargets(targets, int(config.Config.MaxConcurrency))
+	if err != nil {
+		errorMsg := fmt.Sprintf("Error collecting metrics for %d hosts: %v", len(targets), err)
+		log.Error("%v", errorMsg)
+		http.Error(rsp, errorMsg, http.StatusInternalServerError)
+		return
+	}
+
+	log.Debug("Metrics for %d hosts collected", len(targets))
+
+	writeMetrics(rsp, req, metrics)
+}
+
+func writeMetrics(rsp http.ResponseWriter, req *http.Request, metrics string) {
+	var err error
+
 	header := rsp.Header()
 	header.Set(contentTypeHeader, "text/plain")
 
@@ -121,6 +274,22 @@ func metricsHandler(rsp http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// uniqueTargets returns the non-empty targets in order of first appearance.
+func uniqueTargets(targets []string) []string {
+	list := []string{}
+	seen := map[string]bool{}
+
+	for _, t := range targets {
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		list = append(list, t)
+	}
+
+	return list
+}
+
 // gzipAccepted returns whether the client will accept gzip-encoded content.
 func gzipAccepted(header http.Header) bool {
 	a := header.Get(acceptEncodingHeader)
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestUniqueTargets(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"single", "target=bmc-1", []string{"bmc-1"}},
+		{"several", "target=bmc-1&target=bmc-2", []string{"bmc-1", "bmc-2"}},
+		{"repeated", "target=bmc-2&target=bmc-1&target=bmc-2&target=bmc-1", []string{"bmc-2", "bmc-1"}},
+		{"empty", "target=&target=bmc-1&target=", []string{"bmc-1"}},
+		{"none", "", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("invalid query: %v", err)
+			}
+			if got := uniqueTargets(query["target"]); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("uniqueTargets = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
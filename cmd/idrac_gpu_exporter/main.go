@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
+	"github.com/smc-public/idrac_gpu_exporter/internal/collector"
 	"github.com/smc-public/idrac_gpu_exporter/internal/config"
 	"github.com/smc-public/idrac_gpu_exporter/internal/log"
 	"github.com/smc-public/idrac_gpu_exporter/internal/version"
@@ -35,8 +39,24 @@ func main() {
 		log.SetLevel(log.LevelDebug)
 	}
 
+	// Remove the push subscriptions from the hosts on shutdown, since the
+	// BMCs only have a few subscription slots
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		sig := <-signals
+		log.Info("Received %v, shutting down", sig)
+		collector.Shutdown()
+		os.Exit(0)
+	}()
+
 	http.HandleFunc("/metrics", metricsHandler)
+	http.HandleFunc("/metrics/all", allMetricsHandler)
 	http.HandleFunc("/health", healthHandler)
+	http.HandleFunc("/sd", sdHandler)
+	http.HandleFunc("/inventory", inventoryHandler)
+	http.HandleFunc("/events", eventsHandler)
+	http.HandleFunc("/events/push", pushHandler)
 	http.HandleFunc("/reset", resetHandler)
 	http.HandleFunc("/", rootHandler)
 
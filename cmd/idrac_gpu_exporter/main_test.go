@@ -19,8 +19,38 @@ import (
 )
 
 func TestMain(t *testing.T) {
-    // Start a mock Redfish server serving content sourced from testdata/content
-	contentDir := filepath.Join("testdata", "content")
+    // Start the exporter
+    if cmd, err := startExporter();err != nil {
+        t.Fatalf("Failed to start exporter: %v", err)
+    } else {
+        defer stopExporter(cmd)
+    }
+
+    t.Run("dell", func(t *testing.T) {
+        testGolden(t, filepath.Join("testdata", "content"), filepath.Join("testdata", "expected.txt"))
+    })
+
+    t.Run("supermicro", func(t *testing.T) {
+        testGolden(t, filepath.Join("testdata", "supermicro", "content"), filepath.Join("testdata", "supermicro", "expected.txt"))
+    })
+
+    t.Run("lenovo", func(t *testing.T) {
+        testGolden(t, filepath.Join("testdata", "lenovo", "content"), filepath.Join("testdata", "lenovo", "expected.txt"))
+    })
+
+    t.Run("intel", func(t *testing.T) {
+        testGolden(t, filepath.Join("testdata", "intel", "content"), filepath.Join("testdata", "intel", "expected.txt"))
+    })
+
+    t.Run("telemetry", func(t *testing.T) {
+        testGolden(t, filepath.Join("testdata", "telemetry", "content"), filepath.Join("testdata", "telemetry", "expected.txt"))
+    })
+}
+
+// testGolden compares the metrics collected by the exporter from a mock
+// Redfish server, serving content sourced from contentDir, with the metrics
+// in expectedFile
+func testGolden(t *testing.T, contentDir string, expectedFile string) {
 	handler := fileHandler(contentDir)
 	server := httptest.NewTLSServer(http.HandlerFunc(handler))
 	defer server.Close()
@@ -31,13 +61,6 @@ func TestMain(t *testing.T) {
 		t.Fatalf("Failed to split host and port from URL: %v", err)
 	}
 
-    // Start the exporter
-    if cmd, err := startExporter();err != nil {
-        t.Fatalf("Failed to start exporter: %v", err)
-    } else {
-        defer stopExporter(cmd)
-    }
-
     // Get metrics from the exporter
     resp, err := get("http://localhost:9349/metrics?target=" + net.JoinHostPort(test_host, test_port))
     if err != nil {
@@ -45,18 +68,27 @@ func TestMain(t *testing.T) {
     }
 
     // Read expected metrics from file
-    expectedContent, err := readTestFile("testdata", "expected.txt")
+    expectedContent, err := readTestFile(expectedFile)
     if err != nil {
         t.Fatalf("Failed to read expected file: %v", err)
     }
 
-    // Compare the metrics excluding go build version
-    re := regexp.MustCompile(`"go[0-9]+.[0-9]+.[0-9]+`)
-    if re.ReplaceAllString(resp, "") != re.ReplaceAllString(expectedContent,"") {
+    // Compare the metrics excluding go build version and timestamps
+    if normalizeMetrics(resp) != normalizeMetrics(expectedContent) {
         t.Fatalf("Metrics do not match expected content.\nGot:\n%s\nExpected:\n%s", resp, expectedContent)
     }
 }
 
+// normalizeMetrics removes the values that depend on the environment, i.e.
+// the Go version and the timestamps of the scrape, which are written as
+// <timestamp> in the expected files.
+func normalizeMetrics(metrics string) string {
+    re := regexp.MustCompile(`"go[0-9]+.[0-9]+.[0-9]+`)
+    metrics = re.ReplaceAllString(metrics, "")
+    re = regexp.MustCompile(`(?m)^(\w+_timestamp_seconds(\{.*\})?) .*$`)
+    return re.ReplaceAllString(metrics, "$1")
+}
+
 func fileHandler(baseDir string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		fileName := filepath.Clean(r.URL.Path)
@@ -98,7 +130,7 @@ func get(url string) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", err
+		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
 	}
 
 	bodyBytes, err := io.ReadAll(resp.Body)
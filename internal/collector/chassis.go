@@ -0,0 +1,179 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+)
+
+// RefreshChassis emits the temperatures, fans and power supplies of the
+// chassis holding the GPUs, when the chassis metrics are enabled, and the
+// power cap of the system. Newer firmware exposes these in the thermal and
+// power subsystems, older firmware only in the legacy thermal and power
+// resources.
+func (client *Client) RefreshChassis(mc *Collector, ch chan<- prometheus.Metric) bool {
+	if client.chassisPath == "" {
+		return false
+	}
+
+	chassis := Chassis{}
+	ok := client.redfish.Get(client.chassisPath, &chassis)
+	if !ok {
+		return false
+	}
+
+	all := config.Config.Metrics.Chassis
+	if all && chassis.ThermalSubsystem.OdataId != "" {
+		client.refreshThermalSubsystem(mc, ch, chassis.ThermalSubsystem.OdataId)
+	} else if all && chassis.Thermal.OdataId != "" {
+		client.refreshThermal(mc, ch, chassis.Thermal.OdataId)
+	}
+
+	env := EnvironmentMetrics{}
+	if chassis.EnvironmentMetrics.OdataId != "" && !client.redfish.Get(chassis.EnvironmentMetrics.OdataId, &env) {
+		env = EnvironmentMetrics{}
+	}
+
+	// The legacy power resource is also read for the power cap, when the
+	// environment metrics do not report it
+	power := PowerResponse{}
+	if chassis.Power.OdataId != "" && (env.PowerLimitWatts == nil || all && chassis.PowerSubsystem.OdataId == "") {
+		if !client.redfish.Get(chassis.Power.OdataId, &power) {
+			power = PowerResponse{}
+		}
+	}
+
+	if all && chassis.PowerSubsystem.OdataId != "" {
+		client.refreshPowerSubsystem(mc, ch, chassis.PowerSubsystem.OdataId)
+		if env.PowerWatts != nil {
+			mc.NewChassisConsumedPowerWatt(ch, env.PowerWatts.Reading)
+		}
+	} else if all {
+		client.refreshPower(mc, ch, &power)
+	}
+
+	if env.PowerLimitWatts != nil {
+		enabled := env.PowerLimitWatts.ControlMode != "Disabled" && env.PowerLimitWatts.SetPoint != nil
+		mc.NewChassisPowerCap(ch, enabled, env.PowerLimitWatts.SetPoint)
+	} else if len(power.PowerControl) > 0 && power.PowerControl[0].PowerLimit != nil {
+		// The limit is reported as null or zero when power capping is disabled
+		limit := power.PowerControl[0].PowerLimit.LimitInWatts
+		mc.NewChassisPowerCap(ch, limit != nil && *limit > 0, limit)
+	}
+
+	return true
+}
+
+func (client *Client) refreshThermalSubsystem(mc *Collector, ch chan<- prometheus.Metric, path string) {
+	thermal := ThermalSubsystem{}
+	if ok := client.redfish.Get(path, &thermal); !ok {
+		return
+	}
+
+	if thermal.ThermalMetrics.OdataId != "" {
+		metrics := ThermalMetrics{}
+		ok := client.redfish.Get(thermal.ThermalMetrics.OdataId, &metrics)
+		if ok && metrics.TemperatureSummaryCelsius != nil {
+			if v := metrics.TemperatureSummaryCelsius.Intake; v != nil {
+				mc.NewChassisInletTemperatureCelsius(ch, v.Reading)
+			}
+			if v := metrics.TemperatureSummaryCelsius.Exhaust; v != nil {
+				mc.NewChassisExhaustTemperatureCelsius(ch, v.Reading)
+			}
+		}
+	}
+
+	if thermal.Fans.OdataId != "" {
+		group := GroupResponse{}
+		if ok := client.redfish.Get(thermal.Fans.OdataId, &group); !ok {
+			return
+		}
+
+		for _, c := range group.Members.GetLinks() {
+			fan := Fan{}
+			if ok := client.redfish.Get(c, &fan); !ok {
+				continue
+			}
+			if fan.SpeedPercent != nil {
+				mc.NewChassisFanSpeedPercent(ch, fan.SpeedPercent.Reading, fan.Id, fan.Name)
+				mc.NewChassisFanSpeedRPM(ch, fan.SpeedPercent.SpeedRPM, fan.Id, fan.Name)
+			}
+		}
+	}
+}
+
+func (client *Client) refreshPowerSubsystem(mc *Collector, ch chan<- prometheus.Metric, path string) {
+	power := PowerSubsystem{}
+	if ok := client.redfish.Get(path, &power); !ok {
+		return
+	}
+
+	group := GroupResponse{}
+	if ok := client.redfish.Get(power.PowerSupplies.OdataId, &group); !ok {
+		return
+	}
+
+	for _, c := range group.Members.GetLinks() {
+		psu := PowerSupply{}
+		if ok := client.redfish.Get(c, &psu); !ok || psu.Metrics.OdataId == "" {
+			continue
+		}
+
+		metrics := PowerSupplyMetrics{}
+		if ok := client.redfish.Get(psu.Metrics.OdataId, &metrics); !ok {
+			continue
+		}
+		if metrics.InputPowerWatts != nil {
+			mc.NewChassisPSUInputPowerWatt(ch, metrics.InputPowerWatts.Reading, psu.Id)
+		}
+		if metrics.OutputPowerWatts != nil {
+			mc.NewChassisPSUOutputPowerWatt(ch, metrics.OutputPowerWatts.Reading, psu.Id)
+		}
+	}
+}
+
+func (client *Client) refreshThermal(mc *Collector, ch chan<- prometheus.Metric, path string) {
+	thermal := ThermalResponse{}
+	if ok := client.redfish.Get(path, &thermal); !ok {
+		return
+	}
+
+	// Only the first sensor of each kind is reported, since some systems
+	// have an inlet sensor per board
+	inlet, exhaust := false, false
+	for _, t := range thermal.Temperatures {
+		switch {
+		case !inlet && (t.PhysicalContext == "Intake" || strings.Contains(t.Name, "Inlet")):
+			mc.NewChassisInletTemperatureCelsius(ch, t.ReadingCelsius)
+			inlet = true
+		case !exhaust && (t.PhysicalContext == "Exhaust" || strings.Contains(t.Name, "Exhaust")):
+			mc.NewChassisExhaustTemperatureCelsius(ch, t.ReadingCelsius)
+			exhaust = true
+		}
+	}
+
+	for _, f := range thermal.Fans {
+		switch f.ReadingUnits {
+		case "RPM":
+			mc.NewChassisFanSpeedRPM(ch, f.Reading, f.MemberId, f.Name)
+		case "Percent":
+			mc.NewChassisFanSpeedPercent(ch, f.Reading, f.MemberId, f.Name)
+		}
+	}
+}
+
+func (client *Client) refreshPower(mc *Collector, ch chan<- prometheus.Metric, power *PowerResponse) {
+	if len(power.PowerControl) > 0 {
+		mc.NewChassisConsumedPowerWatt(ch, power.PowerControl[0].PowerConsumedWatts)
+	}
+
+	for _, p := range power.PowerSupplies {
+		mc.NewChassisPSUInputPowerWatt(ch, p.PowerInputWatts, p.MemberId)
+		if p.PowerOutputWatts != nil {
+			mc.NewChassisPSUOutputPowerWatt(ch, p.PowerOutputWatts, p.MemberId)
+		} else {
+			mc.NewChassisPSUOutputPowerWatt(ch, p.LastPowerOutputWatts, p.MemberId)
+		}
+	}
+}
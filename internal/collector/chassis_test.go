@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+)
+
+// The power cap is collected regardless of the chassis metrics, from the
+// environment metrics of the chassis or else from the legacy power resource.
+func TestRefreshChassisPowerCap(t *testing.T) {
+	tests := []struct {
+		name    string
+		chassis bool
+		content map[string]string
+		want    map[string]float64
+	}{
+		{
+			name: "environment metrics",
+			content: map[string]string{
+				"/redfish/v1/Chassis/1":                    `{"EnvironmentMetrics":{"@odata.id":"/redfish/v1/Chassis/1/EnvironmentMetrics"},"Power":{"@odata.id":"/redfish/v1/Chassis/1/Power"}}`,
+				"/redfish/v1/Chassis/1/EnvironmentMetrics": `{"PowerWatts":{"Reading":4200},"PowerLimitWatts":{"SetPoint":6000,"ControlMode":"Automatic"}}`,
+				"/redfish/v1/Chassis/1/Power":              `{"PowerControl":[{"PowerConsumedWatts":4100,"PowerLimit":{"LimitInWatts":5000}}]}`,
+			},
+			want: map[string]float64{"power_cap_enabled": 1, "power_cap_watt": 6000},
+		},
+		{
+			name:    "legacy fallback",
+			chassis: true,
+			content: map[string]string{
+				"/redfish/v1/Chassis/1":                    `{"EnvironmentMetrics":{"@odata.id":"/redfish/v1/Chassis/1/EnvironmentMetrics"},"PowerSubsystem":{"@odata.id":"/redfish/v1/Chassis/1/PowerSubsystem"},"Power":{"@odata.id":"/redfish/v1/Chassis/1/Power"}}`,
+				"/redfish/v1/Chassis/1/EnvironmentMetrics": `{"PowerWatts":{"Reading":4200}}`,
+				"/redfish/v1/Chassis/1/Power":              `{"PowerControl":[{"PowerConsumedWatts":4100,"PowerLimit":{"LimitInWatts":5000}}]}`,
+			},
+			want: map[string]float64{"consumed_power_watt": 4200, "power_cap_enabled": 1, "power_cap_watt": 5000},
+		},
+		{
+			name: "legacy disabled",
+			content: map[string]string{
+				"/redfish/v1/Chassis/1":       `{"Power":{"@odata.id":"/redfish/v1/Chassis/1/Power"}}`,
+				"/redfish/v1/Chassis/1/Power": `{"PowerControl":[{"PowerConsumedWatts":4100,"PowerLimit":{"LimitInWatts":null}}]}`,
+			},
+			want: map[string]float64{"power_cap_enabled": 0},
+		},
+		{
+			name: "no power cap",
+			content: map[string]string{
+				"/redfish/v1/Chassis/1": `{}`,
+			},
+			want: map[string]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				body, ok := tt.content[r.URL.Path]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(body))
+			})
+			client.chassisPath = "/redfish/v1/Chassis/1"
+			config.Config.Metrics.Chassis = tt.chassis
+
+			ch, wait := drain()
+			ok := client.RefreshChassis(mc, ch)
+			metrics := wait()
+			if !ok {
+				t.Fatal("RefreshChassis failed")
+			}
+
+			got := map[string]float64{}
+			for _, m := range metrics {
+				_, v, _ := readMetric(t, m)
+				switch m.Desc() {
+				case mc.ChassisConsumedPowerWatt:
+					got["consumed_power_watt"] = v
+				case mc.ChassisPowerCapEnabled:
+					got["power_cap_enabled"] = v
+				case mc.ChassisPowerCapWatt:
+					got["power_cap_watt"] = v
+				default:
+					t.Errorf("unexpected metric %s", m.Desc())
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("metrics = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,12 @@
 package collector
 
 import (
-	"fmt"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+	"github.com/smc-public/idrac_gpu_exporter/internal/log"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -18,13 +20,30 @@ const (
 	INVENTEC
 	FUJITSU
 	SUPERMICRO
+	NVIDIA
 )
 
 type Client struct {
-	redfish     *Redfish
-	vendor      int
-	systemPath  string
-	procPath    string
+	redfish      *Redfish
+	vendor       int
+	adapter      VendorAdapter
+	systemPath   string
+	procPath     string
+	chassisPath  string
+	chassisGroup string
+	updatePath   string
+	firmwareMu   sync.Mutex
+	firmware     []SoftwareInventory
+	firmwareTime time.Time
+	firmwareBusy bool
+	firmwareTried bool
+	pcieDevices  []string
+	pcieSerials  map[string]string
+	telemetryPath string
+	telemetryRetry time.Time
+	eventPath     string
+	managersPath  string
+	logServices   []string
 }
 
 type GPUInfo struct {
@@ -34,6 +53,8 @@ type GPUInfo struct {
 	PartNumber            string
 	SerialNumber          string
 	UUID                 string
+	MemoryType            string
+	AcceleratorType       string
 }
 
 func NewClient(h *config.HostConfig) *Client {
@@ -68,6 +89,11 @@ func (client *Client) findAllEndpoints() bool {
 		return false
 	}
 
+	client.updatePath = root.UpdateService.OdataId
+	client.telemetryPath = root.TelemetryService.OdataId
+	client.eventPath = root.EventService.OdataId
+	client.managersPath = root.Managers.OdataId
+
 	// System
 	ok = client.redfish.Get(root.Systems.OdataId, &group)
 	if !ok {
@@ -82,6 +108,19 @@ func (client *Client) findAllEndpoints() bool {
 	}
 
 	client.procPath = system.Processors.OdataId
+	client.pcieDevices = system.PCIeDevices.GetLinks()
+
+	// Chassis
+	client.chassisGroup = root.Chassis.OdataId
+	if len(system.Links.Chassis) > 0 {
+		client.chassisPath = system.Links.Chassis[0].OdataId
+	} else if root.Chassis.OdataId != "" {
+		chassis := GroupResponse{}
+		ok = client.redfish.Get(root.Chassis.OdataId, &chassis)
+		if ok && len(chassis.Members) > 0 {
+			client.chassisPath = chassis.Members[0].OdataId
+		}
+	}
 
 	// Vendor
 	m := strings.ToLower(system.Manufacturer)
@@ -101,42 +140,48 @@ func (client *Client) findAllEndpoints() bool {
 		client.vendor = FUJITSU
 	} else if strings.Contains(m, "supermicro") {
 		client.vendor = SUPERMICRO
+	} else if strings.Contains(m, "nvidia") {
+		client.vendor = NVIDIA
+	}
+
+	client.adapter = NewVendorAdapter(client.vendor)
+	if _, ok := client.adapter.(*genericAdapter); ok {
+		log.Info("No OEM resources read for %s (%s), using the standard Redfish resources", client.redfish.hostname, system.Manufacturer)
 	}
 
 	return true
 }
 
-func (client *Client) RefreshGPUs(mc *Collector, ch chan<- prometheus.Metric) bool {
-	group := GroupResponse{}
-	ok := client.redfish.Get(client.procPath, &group)
+// RefreshSystem emits the information, power state and health of the system,
+// which tells a powered off host apart from a host with missing GPUs.
+func (client *Client) RefreshSystem(mc *Collector, ch chan<- prometheus.Metric) bool {
+	system := SystemResponse{}
+	ok := client.redfish.Get(client.systemPath, &system)
 	if !ok {
 		return false
 	}
 
-	// Get inventory information for Dell GPUs
-
-	dellVideo := DellVideo{}
+	mc.NewSystemInfo(ch, &system)
+	mc.NewSystemPowerOn(ch, &system)
+	mc.NewSystemHealth(ch, &system)
 
-	if client.vendor == DELL {
-		// Get dell video inventory
+	return true
+}
 
-		dellVideoPath := fmt.Sprintf("%s/Oem/Dell/DellVideo", client.systemPath)
-		client.redfish.Get(dellVideoPath, &dellVideo)
-		dellGPUSensorPath := fmt.Sprintf("%s/Oem/Dell/DellGPUSensors", client.systemPath)
+// RefreshGPUs emits the metrics of the GPUs. The processor metrics of the GPUs
+// are read from the processors, unless telemetry holds the processor metrics
+// read from the metric reports.
+func (client *Client) RefreshGPUs(mc *Collector, ch chan<- prometheus.Metric, telemetry map[string]*GPUMetrics) bool {
+	group := GroupResponse{}
+	ok := client.redfish.Get(client.procPath, &group)
+	if !ok {
+		return false
+	}
 
-		// Get dell GPU sensor metrics
+	// Get vendor specific resources and firmware inventory
 
-		dellGPUSensors := DellGPUSensors{}
-		if ok := client.redfish.Get(dellGPUSensorPath, &dellGPUSensors); ok {
-			for _, v := range dellGPUSensors.Members {
-				mc.NewBoardPowerSupplyStatus(ch, &v)
-				mc.NewMemoryTemperatureCelsius(ch, &v)
-				mc.NewPowerBrakeStatus(ch, &v)
-				mc.NewPrimaryGPUTemperatureCelsius(ch, &v)
-				mc.NewThermalAlertStatus(ch, &v)
-			}
-		}
-	}
+	client.adapter.Prepare(client)
+	client.refreshFirmware()
 
 	// Get GPU metrics
 
@@ -147,50 +192,129 @@ func (client *Client) RefreshGPUs(mc *Collector, ch chan<- prometheus.Metric) bo
 			continue
 		}
 
-		if resp.ProcessorType != "GPU" {
+		if !collectProcessorType(resp.ProcessorType) {
 			continue
 		}
 
-		if resp.Status.State != StateEnabled {
-			continue
-		}
+		mc.NewGPURedfishState(ch, resp.Id, &resp.Status)
+		mc.NewGPURedfishHealth(ch, resp.Id, &resp.Status)
 
 		gpuInfo := GPUInfo{}
 		gpuInfo.Id = resp.Id
 		gpuInfo.Manufacturer = resp.Manufacturer
 		gpuInfo.Model = resp.Model
 		gpuInfo.PartNumber = resp.PartNumber
+		gpuInfo.SerialNumber = resp.SerialNumber
+		gpuInfo.UUID = resp.UUID
+		gpuInfo.MemoryType = resp.MemorySummary.MemoryType
+		gpuInfo.AcceleratorType = resp.ProcessorType
+
+		client.adapter.Identify(&resp, &gpuInfo)
+		client.adapter.Status(mc, ch, &resp)
 
-		if client.vendor == DELL {
-			for _, v := range dellVideo.Members {
-				if v.Id == resp.Id {
-					gpuInfo.UUID = v.GPUGUID
-					gpuInfo.SerialNumber = v.SerialNumber
-					mc.NewGPUState(ch, &v)
-					mc.NewGPUHealth(ch, &v)
-					break
+		mc.NewGPUInfo(ch, &gpuInfo)
+		mc.NewGPUInventory(ch, gpuInfo.Id, mc.inventory.Update(&gpuInfo, time.Now()))
+		mc.NewGPUMemoryTotalBytes(ch, &resp)
+		mc.NewGPUECCModeEnabled(ch, &resp)
+
+		// The firmware version of the processor is usually also listed in
+		// the firmware inventory (e.g. as the VBIOS on Dell)
+		firmware := client.gpuFirmware(c, resp.Id)
+		listed := false
+		for _, v := range firmware {
+			mc.NewGPUFirmwareInfo(ch, resp.Id, v.Name, v.Version)
+			listed = listed || v.Version == resp.FirmwareVersion
+		}
+		if resp.FirmwareVersion != "" && !listed {
+			mc.NewGPUFirmwareInfo(ch, resp.Id, "processor", resp.FirmwareVersion)
+		}
+
+		// The DMTF power limit and TDP take precedence over the OEM ones
+		oemLimit, tdp := client.adapter.PowerLimits(&resp)
+		if resp.TDPWatts != nil {
+			tdp = resp.TDPWatts
+		}
+		if tdp != nil {
+			mc.NewGPUTDPWatt(ch, *tdp, resp.Id)
+		}
+
+		// GPUs that are not enabled (e.g. absent or offline) are still
+		// reported above, but their metrics are not read
+		if resp.Status.State != StateEnabled {
+			client.adapter.Sensors(mc, ch, &resp, nil)
+			continue
+		}
+
+		// The headroom is relative to the configured power limit, or the
+		// TDP when no limit is reported
+		var powerLimit *float64
+		thresholds := map[string]float64{}
+		if resp.EnvironmentMetrics.OdataId != "" {
+			env := EnvironmentMetrics{}
+			ok = client.redfish.Get(resp.EnvironmentMetrics.OdataId, &env)
+			if ok && env.PowerLimitWatts != nil && env.PowerLimitWatts.SetPoint != nil {
+				powerLimit = env.PowerLimitWatts.SetPoint
+			}
+
+			// The temperature thresholds are found on the sensor the
+			// temperature reading is sourced from
+			if ok && env.TemperatureCelsius != nil && env.TemperatureCelsius.DataSourceUri != "" {
+				sensor := Sensor{}
+				if ok := client.redfish.Get(env.TemperatureCelsius.DataSourceUri, &sensor); ok {
+					addSensorThresholds(thresholds, &sensor)
 				}
 			}
 		}
 
-		mc.NewGPUInfo(ch, &gpuInfo)
+		if nvidia := resp.Oem.Nvidia; nvidia != nil {
+			if nvidia.SlowdownTemperatureCelsius != nil {
+				thresholds["slowdown"] = *nvidia.SlowdownTemperatureCelsius
+			}
+			if nvidia.ShutdownTemperatureCelsius != nil {
+				thresholds["shutdown"] = *nvidia.ShutdownTemperatureCelsius
+			}
+		}
+		if powerLimit == nil {
+			powerLimit = oemLimit
+		}
+		if powerLimit != nil {
+			mc.NewGPUPowerLimitWatt(ch, *powerLimit, resp.Id)
+		} else {
+			powerLimit = tdp
+		}
+
+		client.adapter.Thresholds(&resp, thresholds)
+		mc.NewGPUTemperatureThresholds(ch, resp.Id, thresholds)
 
-		if resp.Metrics.OdataId != "" {
+		// The processor metrics are taken from the metric reports of the
+		// telemetry service, when these are read. When the processor metrics
+		// can not be read, the other metrics of the GPU are still reported.
+		metrics := telemetry[resp.Id]
+		if metrics == nil && resp.Metrics.OdataId != "" {
 			gpuMetrics := GPUMetrics{}
-			ok = client.redfish.Get(resp.Metrics.OdataId, &gpuMetrics)
-			if !ok {
-				break
+			if ok := client.redfish.Get(resp.Metrics.OdataId, &gpuMetrics); ok {
+				// Not every vendor uses the GPU id as id of the metrics resource
+				gpuMetrics.Id = resp.Id
+				metrics = &gpuMetrics
 			}
+		}
 
-			mc.NewGPUBandwidthPercent(ch, &gpuMetrics)
-			mc.NewGPUConsumedPowerWatt(ch, &gpuMetrics)
-			mc.NewGPUOperatingSpeedMHz(ch, &gpuMetrics)
+		if gpuMetrics := metrics; gpuMetrics != nil {
+			mc.NewGPUBandwidthPercent(ch, gpuMetrics)
+			mc.NewGPUConsumedPowerWatt(ch, gpuMetrics)
+			if power := gpuMetrics.ConsumedPowerWatt; power != nil {
+				if powerLimit != nil {
+					mc.NewGPUPowerHeadroomWatt(ch, *powerLimit-*power, resp.Id)
+				}
+				mc.NewGPUEnergyJoulesTotal(ch, mc.energy.Update(resp.Id, gpuInfo.SerialNumber, *power, time.Now()), resp.Id)
+			}
+			mc.NewGPUOperatingSpeedMHz(ch, gpuMetrics)
 
 			if gpuMetrics.Oem != nil {
 				nvidia := gpuMetrics.Oem.Nvidia
 				if nvidia != nil {
 					mc.NewGPUThrottleReasons(ch, nvidia.ThrottleReasons, gpuMetrics.Id)
-					mc.NewGPUSMUtilizationPercent(ch, nvidia.SMUtilizationPercent, gpuMetrics.Id)
+					mc.NewGPUSMUtilizationPercent(ch, float64(nvidia.SMUtilizationPercent), gpuMetrics.Id)
 					mc.NewGPUSMActivityPercent(ch, nvidia.SMActivityPercent, gpuMetrics.Id)
 					mc.NewGPUSMOccupancyPercent(ch, nvidia.SMOccupancyPercent, gpuMetrics.Id)
 					mc.NewGPUTensorCoreActivityPercent(ch, nvidia.TensorCoreActivityPercent, gpuMetrics.Id)
@@ -204,24 +328,122 @@ func (client *Client) RefreshGPUs(mc *Collector, ch chan<- prometheus.Metric) bo
 					mc.NewGPUMaxSupportedPCIeLinkSpeed(ch, dell.MaxSupportedPCIeLinkSpeed, gpuMetrics.Id)
 					mc.NewGPUDRAMUtilizationPercent(ch, dell.DRAMUtilizationPercent, gpuMetrics.Id)
 				}
+				amd := gpuMetrics.Oem.Amd
+				if amd != nil {
+					if amd.GFXActivityPercent != nil {
+						mc.NewGPUSMUtilizationPercent(ch, *amd.GFXActivityPercent, gpuMetrics.Id)
+					}
+					if amd.UMCActivityPercent != nil && dell == nil {
+						mc.NewGPUDRAMUtilizationPercent(ch, *amd.UMCActivityPercent, gpuMetrics.Id)
+					}
+					mc.NewGPUXGMILinks(ch, gpuMetrics.Id, amd)
+				}
+				intel := gpuMetrics.Oem.Intel
+				if intel != nil {
+					if intel.ComputeUtilizationPercent != nil {
+						mc.NewGPUSMUtilizationPercent(ch, *intel.ComputeUtilizationPercent, gpuMetrics.Id)
+					}
+					if intel.MemoryUtilizationPercent != nil && dell == nil {
+						mc.NewGPUDRAMUtilizationPercent(ch, *intel.MemoryUtilizationPercent, gpuMetrics.Id)
+					}
+				}
 			}
 
 			if gpuMetrics.PCIeErrors != nil {
-				mc.NewGPUPCIeCorrectableErrorCount(ch, gpuMetrics.PCIeErrors.CorrectableErrorCount, gpuMetrics.Id)
+				mc.NewGPUPCIeErrors(ch, gpuMetrics.Id, gpuInfo.SerialNumber, gpuMetrics.PCIeErrors)
 			}
 		}
 
-		if resp.MemorySummary.Metrics.OdataId != "" {
-			gpuMemoryMetrics := GPUMemoryMetrics{}
-			ok = client.redfish.Get(resp.MemorySummary.Metrics.OdataId, &gpuMemoryMetrics)
-			if !ok {
-				break
-			}
+		client.adapter.Sensors(mc, ch, &resp, metrics)
+		client.refreshPCIeLink(mc, ch, &resp)
 
+		gpuMemoryMetrics := GPUMemoryMetrics{}
+		if resp.MemorySummary.Metrics.OdataId != "" && client.redfish.Get(resp.MemorySummary.Metrics.OdataId, &gpuMemoryMetrics) {
 			mc.NewGPUMemoryBandwidthPercent(ch, resp.Id, &gpuMemoryMetrics)
 			mc.NewGPUMemoryOperatingSpeedMHz(ch, resp.Id, &gpuMemoryMetrics)
+			mc.NewGPUMemoryECCErrors(ch, resp.Id, "current_boot", gpuMemoryMetrics.CurrentPeriod)
+			mc.NewGPUMemoryECCErrors(ch, resp.Id, "lifetime", gpuMemoryMetrics.LifeTime)
+
+			if gpuMemoryMetrics.Oem != nil && gpuMemoryMetrics.Oem.Nvidia != nil {
+				mc.NewGPUMemoryRowRemapping(ch, resp.Id, gpuMemoryMetrics.Oem.Nvidia)
+			}
+		}
+
+		if resp.Ports.OdataId != "" {
+			client.refreshNVLinkPorts(mc, ch, resp.Id, resp.Ports.OdataId)
+		}
+
+		if resp.SubProcessors.OdataId != "" {
+			client.refreshMIGInstances(mc, ch, resp.Id, resp.SubProcessors.OdataId)
 		}
 	}
 
+	mc.NewGPUHostEnergyJoulesTotal(ch, mc.energy.HostJoules())
+
 	return true
 }
+
+// refreshNVLinkPorts emits the state and counters of the NVLink ports of a GPU,
+// other kinds of ports are ignored.
+func (client *Client) refreshNVLinkPorts(mc *Collector, ch chan<- prometheus.Metric, gpu, path string) {
+	group := GroupResponse{}
+	if ok := client.redfish.Get(path, &group); !ok {
+		return
+	}
+
+	for _, c := range group.Members.GetLinks() {
+		port := Port{}
+		if ok := client.redfish.Get(c, &port); !ok {
+			continue
+		}
+
+		if port.PortProtocol != "NVLink" {
+			continue
+		}
+
+		mc.NewGPUNVLinkPort(ch, gpu, &port)
+
+		if port.Metrics.OdataId != "" {
+			portMetrics := PortMetrics{}
+			if ok := client.redfish.Get(port.Metrics.OdataId, &portMetrics); ok {
+				mc.NewGPUNVLinkPortMetrics(ch, gpu, port.Id, &portMetrics)
+			}
+		}
+	}
+}
+
+// refreshMIGInstances emits the info and utilization of the MIG instances of
+// a GPU, which are exposed as its sub-processors.
+func (client *Client) refreshMIGInstances(mc *Collector, ch chan<- prometheus.Metric, gpu, path string) {
+	group := GroupResponse{}
+	if ok := client.redfish.Get(path, &group); !ok {
+		return
+	}
+
+	for _, c := range group.Members.GetLinks() {
+		instance := GPU{}
+		if ok := client.redfish.Get(c, &instance); !ok {
+			continue
+		}
+
+		mc.NewGPUMIGInfo(ch, gpu, &instance)
+
+		if instance.Metrics.OdataId != "" {
+			metrics := GPUMetrics{}
+			if ok := client.redfish.Get(instance.Metrics.OdataId, &metrics); ok {
+				mc.NewGPUMIGMetrics(ch, gpu, instance.Id, &metrics)
+			}
+		}
+	}
+}
+
+// collectProcessorType reports whether processors of the given Redfish
+// processor type are collected, as selected in the configuration
+func collectProcessorType(t string) bool {
+	for _, p := range config.Config.ProcessorTypes {
+		if t == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package collector
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+)
+
+// A GPU whose processor or memory metrics can not be read does not hide the
+// metrics of the other GPUs, nor its own Dell OEM sensors.
+func TestRefreshGPUsMetricsFailure(t *testing.T) {
+	client, mc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1/Systems/System.Embedded.1/Processors":
+			_, _ = w.Write([]byte(`{"Members":[
+				{"@odata.id":"/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.21-1"},
+				{"@odata.id":"/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.22-1"}
+			]}`))
+		case "/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.21-1",
+			"/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.22-1":
+			id := r.URL.Path[len("/redfish/v1/Systems/System.Embedded.1/Processors/"):]
+			_, _ = w.Write([]byte(`{"Id":"` + id + `","ProcessorType":"GPU","Status":{"State":"Enabled","Health":"OK"},
+				"Metrics":{"@odata.id":"` + r.URL.Path + `/ProcessorMetrics"},
+				"MemorySummary":{"Metrics":{"@odata.id":"` + r.URL.Path + `/MemorySummary/MemoryMetrics"}}}`))
+		case "/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.22-1/ProcessorMetrics":
+			_, _ = w.Write([]byte(`{"ConsumedPowerWatt":310}`))
+		case "/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.22-1/MemorySummary/MemoryMetrics":
+			_, _ = w.Write([]byte(`{"OperatingSpeedMHz":2619}`))
+		case "/redfish/v1/Systems/System.Embedded.1/Oem/Dell/DellGPUSensors":
+			_, _ = w.Write([]byte(`{"Members":[
+				{"Id":"Video.Slot.21-1","PrimaryGPUTemperatureCelsius":41},
+				{"Id":"Video.Slot.22-1","PrimaryGPUTemperatureCelsius":43}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	config.Config.ProcessorTypes = []string{"GPU"}
+	client.systemPath = "/redfish/v1/Systems/System.Embedded.1"
+	client.procPath = client.systemPath + "/Processors"
+	client.adapter = &dellAdapter{}
+	mc.energy = newEnergyMeter("bmc-1")
+	mc.inventory = newInventory("bmc-1")
+	mc.counters = newCounterTracker("bmc-1")
+
+	ch, wait := drain()
+	ok := client.RefreshGPUs(mc, ch, nil)
+	metrics := wait()
+	if !ok {
+		t.Fatal("RefreshGPUs failed")
+	}
+
+	temperatures := map[string]float64{}
+	power := map[string]float64{}
+	memorySpeed := map[string]float64{}
+	for _, m := range metrics {
+		labels, v, _ := readMetric(t, m)
+		switch m.Desc() {
+		case mc.GPUPrimaryGPUTemperatureCelsius:
+			temperatures[labels["id"]] = v
+		case mc.GPUConsumedPowerWatt:
+			power[labels["id"]] = v
+		case mc.GPUMemoryOperatingSpeedMHz:
+			memorySpeed[labels["id"]] = v
+		}
+	}
+
+	if len(temperatures) != 2 || temperatures["Video.Slot.21-1"] != 41 || temperatures["Video.Slot.22-1"] != 43 {
+		t.Errorf("temperatures = %v, want both GPUs", temperatures)
+	}
+	if len(power) != 1 || power["Video.Slot.22-1"] != 310 {
+		t.Errorf("consumed power = %v, want Video.Slot.22-1 only", power)
+	}
+	if len(memorySpeed) != 1 || memorySpeed["Video.Slot.22-1"] != 2619 {
+		t.Errorf("memory speed = %v, want Video.Slot.22-1 only", memorySpeed)
+	}
+}
+
+// A GPU missing from the metric reports is read from its processor metrics,
+// and a GPU without a power reading in the reports gets no energy or power
+// headroom.
+func TestRefreshGPUsTelemetryMissing(t *testing.T) {
+	crawled := map[string]int{}
+	client, mc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1/Systems/System.Embedded.1/Processors":
+			_, _ = w.Write([]byte(`{"Members":[
+				{"@odata.id":"/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.21-1"},
+				{"@odata.id":"/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.22-1"}
+			]}`))
+		case "/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.21-1",
+			"/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.22-1":
+			id := r.URL.Path[len("/redfish/v1/Systems/System.Embedded.1/Processors/"):]
+			_, _ = w.Write([]byte(`{"Id":"` + id + `","ProcessorType":"GPU","TDPWatts":700,"Status":{"State":"Enabled","Health":"OK"},
+				"Metrics":{"@odata.id":"` + r.URL.Path + `/ProcessorMetrics"}}`))
+		case "/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.21-1/ProcessorMetrics",
+			"/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.22-1/ProcessorMetrics":
+			crawled[r.URL.Path]++
+			_, _ = w.Write([]byte(`{"ConsumedPowerWatt":310}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	config.Config.ProcessorTypes = []string{"GPU"}
+	client.systemPath = "/redfish/v1/Systems/System.Embedded.1"
+	client.procPath = client.systemPath + "/Processors"
+	client.adapter = &genericAdapter{}
+	mc.energy = newEnergyMeter("bmc-1")
+	mc.inventory = newInventory("bmc-1")
+	mc.counters = newCounterTracker("bmc-1")
+
+	temperature := 45.0
+	telemetry := map[string]*GPUMetrics{
+		"Video.Slot.21-1": {Id: "Video.Slot.21-1", TemperatureCelsius: &temperature},
+	}
+
+	ch, wait := drain()
+	ok := client.RefreshGPUs(mc, ch, telemetry)
+	metrics := wait()
+	if !ok {
+		t.Fatal("RefreshGPUs failed")
+	}
+
+	power := map[string]float64{}
+	headroom := map[string]float64{}
+	energy := map[string]bool{}
+	for _, m := range metrics {
+		labels, v, _ := readMetric(t, m)
+		switch m.Desc() {
+		case mc.GPUConsumedPowerWatt:
+			power[labels["id"]] = v
+		case mc.GPUPowerHeadroomWatt:
+			headroom[labels["id"]] = v
+		case mc.GPUEnergyJoulesTotal:
+			energy[labels["id"]] = true
+		}
+	}
+
+	if len(crawled) != 1 || crawled["/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.22-1/ProcessorMetrics"] != 1 {
+		t.Errorf("processor metrics read = %v, want Video.Slot.22-1 only", crawled)
+	}
+	if len(power) != 1 || power["Video.Slot.22-1"] != 310 {
+		t.Errorf("consumed power = %v, want Video.Slot.22-1 only", power)
+	}
+	if len(headroom) != 1 || headroom["Video.Slot.22-1"] != 390 {
+		t.Errorf("power headroom = %v, want Video.Slot.22-1 only", headroom)
+	}
+	if len(energy) != 1 || !energy["Video.Slot.22-1"] {
+		t.Errorf("energy = %v, want Video.Slot.22-1 only", energy)
+	}
+}
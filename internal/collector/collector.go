@@ -9,6 +9,7 @@ import (
 	"sync/atomic"
 
 	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	"github.com/smc-public/idrac_gpu_exporter/internal/config"
 	"github.com/smc-public/idrac_gpu_exporter/internal/version"
@@ -25,15 +26,31 @@ type Collector struct {
 	collecting bool
 	errors     atomic.Uint64
 	builder    *strings.Builder
+	families   []*dto.MetricFamily
+	energy     *energyMeter
+	inventory  *inventory
+	counters   *counterTracker
+	events     *eventLog
+	logs       *logTailer
 
 	// Exporter
 	ExporterBuildInfo         *prometheus.Desc
 	ExporterScrapeErrorsTotal *prometheus.Desc
 
+	// System
+	SystemInfo    *prometheus.Desc
+	SystemPowerOn *prometheus.Desc
+	SystemHealth  *prometheus.Desc
+
 	// GPUs
 	GPUInfo                         *prometheus.Desc
 	GPUState                        *prometheus.Desc
 	GPUHealth                       *prometheus.Desc
+	GPURedfishState                 *prometheus.Desc
+	GPURedfishHealth                *prometheus.Desc
+	GPUSensorTemperatureCelsius     *prometheus.Desc
+	GPUSensorPowerWatt              *prometheus.Desc
+	GPUSensorHealth                 *prometheus.Desc
 	GPUBoardPowerSupplyStatus       *prometheus.Desc
 	GPUMemoryTemperatureCelsius     *prometheus.Desc
 	GPUPowerBrakeStatus             *prometheus.Desc
@@ -56,166 +73,561 @@ type Collector struct {
 	GPUMaxSupportedPCIeLinkSpeed    *prometheus.Desc
 	GPUDRAMUtilizationPercent       *prometheus.Desc
 	GPUPCIeCorrectableErrorCount    *prometheus.Desc
+	GPUPCIeErrors                   *prometheus.Desc
+	GPUEnergyJoulesTotal            *prometheus.Desc
+	GPUHostEnergyJoulesTotal        *prometheus.Desc
+	GPUInventoryChangesTotal        *prometheus.Desc
+	GPUInfoLastChangeTimestamp      *prometheus.Desc
+	GPUMemoryCorrectableECCErrors   *prometheus.Desc
+	GPUMemoryUncorrectableECCErrors *prometheus.Desc
+	GPUMemoryCorrectableRowRemaps   *prometheus.Desc
+	GPUMemoryUncorrectableRowRemaps *prometheus.Desc
+	GPUMemoryRowRemappingPending    *prometheus.Desc
+	GPUMemoryRowRemappingFailed     *prometheus.Desc
+	GPUMemoryTotalBytes             *prometheus.Desc
+	GPUECCModeEnabled               *prometheus.Desc
+	GPUFirmwareInfo                 *prometheus.Desc
+	GPUTDPWatt                      *prometheus.Desc
+	GPUPowerLimitWatt               *prometheus.Desc
+	GPUPowerHeadroomWatt            *prometheus.Desc
+	GPUNVLinkStatus                 *prometheus.Desc
+	GPUNVLinkSpeedGbps              *prometheus.Desc
+	GPUNVLinkTransmitBytes          *prometheus.Desc
+	GPUNVLinkReceiveBytes           *prometheus.Desc
+	GPUNVLinkReplayErrors           *prometheus.Desc
+	GPUNVLinkRecoveryErrors         *prometheus.Desc
+	GPUNVLinkCRCErrors              *prometheus.Desc
+	GPUXGMISpeedGbps                *prometheus.Desc
+	GPUXGMIReadBytes                *prometheus.Desc
+	GPUXGMIWriteBytes               *prometheus.Desc
+	GPUMIGInfo                      *prometheus.Desc
+	GPUMIGMemoryTotalBytes          *prometheus.Desc
+	GPUMIGBandwidthPercent          *prometheus.Desc
+	GPUMIGSMUtilizationPercent      *prometheus.Desc
+	GPUPCIeLinkWidth                *prometheus.Desc
+	GPUPCIeLinkMaxWidth             *prometheus.Desc
+	GPUPCIeLinkGeneration           *prometheus.Desc
+	GPUPCIeLinkMaxGeneration        *prometheus.Desc
+	GPUPCIeLinkDegraded             *prometheus.Desc
+	GPUTemperatureThresholdCelsius  *prometheus.Desc
+	GPUTemperatureCelsius           *prometheus.Desc
+	GPUTelemetryValue               *prometheus.Desc
+	GPUEventsTotal                  *prometheus.Desc
+	GPUEventMetricReportsTotal      *prometheus.Desc
+	GPUEventStreamConnected         *prometheus.Desc
+	GPUEventMetricValue             *prometheus.Desc
+	GPULogEventsTotal               *prometheus.Desc
+
+	// Chassis
+	ChassisInletTemperatureCelsius   *prometheus.Desc
+	ChassisExhaustTemperatureCelsius *prometheus.Desc
+	ChassisFanSpeedRPM               *prometheus.Desc
+	ChassisFanSpeedPercent           *prometheus.Desc
+	ChassisPSUInputPowerWatt         *prometheus.Desc
+	ChassisPSUOutputPowerWatt        *prometheus.Desc
+	ChassisConsumedPowerWatt         *prometheus.Desc
+	ChassisPowerCapEnabled           *prometheus.Desc
+	ChassisPowerCapWatt              *prometheus.Desc
 }
 
-func NewCollector() *Collector {
-	prefix := config.Config.MetricsPrefix
+// NewCollector returns a new collector for the target, where all metrics
+// carry the given constant labels in addition to their own labels.
+func NewCollector(target string, labels prometheus.Labels) (*Collector, error) {
+	collector := newDescriptors(config.Config.MetricsPrefix, labels)
+	collector.energy = newEnergyMeter(target)
+	collector.inventory = newInventory(target)
+	collector.counters = newCounterTracker(target)
+	collector.events = newEventLog()
+	collector.logs = newLogTailer(target)
+	collector.builder = new(strings.Builder)
+	collector.collected = sync.NewCond(new(sync.Mutex))
+	collector.registry = prometheus.NewRegistry()
+	err := collector.registry.Register(collector)
+	if err != nil {
+		return nil, err
+	}
+
+	return collector, nil
+}
+
+// CheckLabels returns an error when the host labels can not be added to the
+// metrics, because they collide with the labels of one of the metrics.
+func CheckLabels(labels prometheus.Labels) error {
+	return prometheus.NewRegistry().Register(newDescriptors("", labels))
+}
+
+// newDescriptors returns a collector holding only the metric descriptors,
+// where all metrics carry the given constant labels.
+func newDescriptors(prefix string, labels prometheus.Labels) *Collector {
+
+	buildInfo := prometheus.Labels{
+		"version":   version.Version,
+		"revision":  version.Revision,
+		"goversion": runtime.Version(),
+	}
+	for k, v := range labels {
+		buildInfo[k] = v
+	}
 
 	collector := &Collector{
 		ExporterBuildInfo: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu_exporter", "build_info"),
 			"Constant metric with build information for the exporter",
-			nil, prometheus.Labels{
-				"version":   version.Version,
-				"revision":  version.Revision,
-				"goversion": runtime.Version(),
-			},
+			nil, buildInfo,
 		),
 		ExporterScrapeErrorsTotal: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu_exporter", "scrape_errors_total"),
 			"Total number of errors encountered while scraping target",
-			nil, nil,
+			nil, labels,
+		),
+		SystemInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "system", "info"),
+			"Information about the host system",
+			[]string{"model", "serial_number", "service_tag", "bios_version", "hostname"}, labels,
+		),
+		SystemPowerOn: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "system", "power_on"),
+			"Whether the host system is powered on",
+			nil, labels,
+		),
+		SystemHealth: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "system", "health"),
+			"Health rollup of the host system, 0=Critical, 1=Warning, 2=OK",
+			[]string{"status"}, labels,
 		),
 		GPUInfo: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "info"),
 			"Information about the GPU",
-			[]string{"id", "manufacturer", "model", "part_number", "serial_number", "uuid"}, nil,
+			[]string{"id", "manufacturer", "model", "part_number", "serial_number", "uuid", "memory_type", "accelerator_type"}, labels,
 		),
 		GPUState: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "state"),
 			"State of the GPU",
-			[]string{"id", "state"}, nil,
+			[]string{"id", "state"}, labels,
 		),
 		GPUHealth: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "health"),
 			"Health status of the GPU",
-			[]string{"id", "status"}, nil,
+			[]string{"id", "status"}, labels,
+		),
+		GPURedfishState: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "redfish_state"),
+			"State of the GPU as reported by Redfish, 0=Enabled, 1=Disabled, 2=StandbyOffline, 3=StandbySpare, 4=InTest, 5=Starting, 6=Absent, 7=UnavailableOffline, 8=Deferring, 9=Quiesced, 10=Updating, 11=Qualified, 12=Degraded",
+			[]string{"id", "state"}, labels,
+		),
+		GPURedfishHealth: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "redfish_health"),
+			"Health of the GPU as reported by Redfish, 0=Critical, 1=Warning, 2=OK",
+			[]string{"id", "status"}, labels,
+		),
+		GPUSensorTemperatureCelsius: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "sensor_temperature_celsius"),
+			"Reading of a temperature sensor of the GPU in degrees Celsius",
+			[]string{"id", "sensor"}, labels,
+		),
+		GPUSensorPowerWatt: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "sensor_power_watt"),
+			"Reading of a power sensor of the GPU in watts",
+			[]string{"id", "sensor"}, labels,
+		),
+		GPUSensorHealth: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "sensor_health"),
+			"Health of a sensor of the GPU, 0=Critical, 1=Warning, 2=OK",
+			[]string{"id", "sensor", "status"}, labels,
 		),
 		GPUBoardPowerSupplyStatus: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "board_power_supply_status"),
 			"Status of the GPU board power supply",
-			[]string{"id", "status"}, nil,
+			[]string{"id", "status"}, labels,
 		),
 		GPUMemoryTemperatureCelsius: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "memory_temperature_celsius"),
 			"Temperature of the GPU memory in celsius",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUPowerBrakeStatus: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "power_brake_status"),
 			"Status of the GPU power brake",
-			[]string{"id", "status"}, nil,
+			[]string{"id", "status"}, labels,
 		),
 		GPUPrimaryGPUTemperatureCelsius: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "primary_gpu_temperature_celsius"),
 			"Primary temperature of the GPU in celsius",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUThermalAlertStatus: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "thermal_alert_status"),
 			"Thermal alert status of the GPU",
-			[]string{"id", "status"}, nil,
+			[]string{"id", "status"}, labels,
 		),
 		GPUBandwidthPercent: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "bandwidth_percent"),
 			"Utilization of the GPU in percent",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUConsumedPowerWatt: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "consumed_power_watt"),
 			"Power consumed by the GPU in watts",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUOperatingSpeedMHz: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "operating_speed_mhz"),
 			"Operating speed of the GPU in Mhz",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUMemoryBandwidthPercent: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "memory_bandwidth_percent"),
 			"Utilization of the GPU memory in percent",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUMemoryOperatingSpeedMHz: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "memory_operating_speed_mhz"),
 			"Operating speed of the GPU memory in Mhz",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUThrottleReason: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "throttle_reason"),
 			"Reason for GPU throttling",
-			[]string{"id", "reason"}, nil,
+			[]string{"id", "reason"}, labels,
 		),
 		GPUSMUtilizationPercent: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "sm_utilization_percent"),
 			"Streaming Multiprocessor (SM) utilization of the GPU in percent",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUSMActivityPercent: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "sm_activity_percent"),
 			"Streaming Multiprocessor (SM) activity of the GPU in percent",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUSMOccupancyPercent: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "sm_occupancy_percent"),
 			"Streaming Multiprocessor (SM) occupancy of the GPU in percent",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUTensorCoreActivityPercent: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "tensor_core_activity_percent"),
 			"Tensor Core activity of the GPU in percent",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUHMMAUtilizationPercent: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "hmma_utilization_percent"),
 			"HMMA (Hybrid Matrix Multiply-Accumulate) utilization of the GPU in percent",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUPCIeRawTxBandwidthGbps: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "pcie_raw_tx_bandwidth_gbps"),
 			"PCIe raw transmit bandwidth of the GPU in Gbps",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUPCIeRawRxBandwidthGbps: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "pcie_raw_rx_bandwidth_gbps"),
 			"PCIe raw receive bandwidth of the GPU in Gbps",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUCurrentPCIeLinkSpeed: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "current_pcie_link_speed"),
 			"Current PCIe link speed of the GPU",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUMaxSupportedPCIeLinkSpeed: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "max_supported_pcie_link_speed"),
 			"Maximum supported PCIe link speed of the GPU",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUDRAMUtilizationPercent: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "dram_utilization_percent"),
 			"DRAM utilization of the GPU in percent",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
 		),
 		GPUPCIeCorrectableErrorCount: prometheus.NewDesc(
 			prometheus.BuildFQName(prefix, "gpu", "pcie_correctable_error_count"),
 			"Number of correctable PCIe errors of the GPU",
-			[]string{"id"}, nil,
+			[]string{"id"}, labels,
+		),
+		GPUPCIeErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "pcie_errors_total"),
+			"Number of PCIe errors and link events of the GPU, by type",
+			[]string{"id", "type"}, labels,
+		),
+		GPUEnergyJoulesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "energy_joules_total"),
+			"Energy consumed by the GPU in joules, integrated from the consumed power",
+			[]string{"id"}, labels,
+		),
+		GPUHostEnergyJoulesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "host_energy_joules_total"),
+			"Energy consumed by all GPUs of the host in joules, integrated from the consumed power",
+			nil, labels,
+		),
+		GPUInventoryChangesTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "inventory_changes_total"),
+			"Number of times a different GPU was detected in the slot",
+			[]string{"id"}, labels,
+		),
+		GPUInfoLastChangeTimestamp: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "info_last_change_timestamp_seconds"),
+			"Time when the GPU in the slot was first seen or last changed, in seconds since epoch",
+			[]string{"id"}, labels,
+		),
+		GPUMemoryCorrectableECCErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "memory_correctable_ecc_errors_total"),
+			"Number of correctable (single-bit) ECC errors of the GPU memory",
+			[]string{"id", "period"}, labels,
+		),
+		GPUMemoryUncorrectableECCErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "memory_uncorrectable_ecc_errors_total"),
+			"Number of uncorrectable (double-bit) ECC errors of the GPU memory",
+			[]string{"id", "period"}, labels,
+		),
+		GPUMemoryCorrectableRowRemaps: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "memory_correctable_row_remappings_total"),
+			"Number of GPU memory rows remapped due to correctable errors",
+			[]string{"id"}, labels,
+		),
+		GPUMemoryUncorrectableRowRemaps: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "memory_uncorrectable_row_remappings_total"),
+			"Number of GPU memory rows remapped due to uncorrectable errors",
+			[]string{"id"}, labels,
+		),
+		GPUMemoryRowRemappingPending: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "memory_row_remapping_pending"),
+			"Whether a GPU memory row remapping is pending until the next GPU reset",
+			[]string{"id"}, labels,
+		),
+		GPUMemoryRowRemappingFailed: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "memory_row_remapping_failed"),
+			"Whether a GPU memory row remapping has failed",
+			[]string{"id"}, labels,
+		),
+		GPUMemoryTotalBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "memory_total_bytes"),
+			"Total memory capacity of the GPU in bytes",
+			[]string{"id"}, labels,
+		),
+		GPUECCModeEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "ecc_mode_enabled"),
+			"Whether ECC mode is enabled for the GPU memory",
+			[]string{"id"}, labels,
+		),
+		GPUFirmwareInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "firmware_info"),
+			"Firmware version of a GPU component",
+			[]string{"id", "component", "version"}, labels,
+		),
+		GPUTDPWatt: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "tdp_watt"),
+			"Thermal design power of the GPU in watts",
+			[]string{"id"}, labels,
+		),
+		GPUPowerLimitWatt: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "power_limit_watt"),
+			"Configured power limit of the GPU in watts",
+			[]string{"id"}, labels,
+		),
+		GPUPowerHeadroomWatt: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "power_headroom_watt"),
+			"Power limit (or TDP, when no limit is reported) minus the consumed power of the GPU in watts",
+			[]string{"id"}, labels,
+		),
+		GPUNVLinkStatus: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "nvlink_status"),
+			"Link status of the GPU NVLink port, 0=LinkDown, 1=LinkUp, 2=NoLink, 3=Starting, 4=Training",
+			[]string{"gpu", "port", "status"}, labels,
+		),
+		GPUNVLinkSpeedGbps: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "nvlink_speed_gbps"),
+			"Current speed of the GPU NVLink port in Gbit/s",
+			[]string{"gpu", "port"}, labels,
+		),
+		GPUNVLinkTransmitBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "nvlink_transmit_bytes_total"),
+			"Number of bytes transmitted on the GPU NVLink port",
+			[]string{"gpu", "port"}, labels,
+		),
+		GPUNVLinkReceiveBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "nvlink_receive_bytes_total"),
+			"Number of bytes received on the GPU NVLink port",
+			[]string{"gpu", "port"}, labels,
+		),
+		GPUNVLinkReplayErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "nvlink_replay_errors_total"),
+			"Number of replay errors on the GPU NVLink port",
+			[]string{"gpu", "port"}, labels,
+		),
+		GPUNVLinkRecoveryErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "nvlink_recovery_errors_total"),
+			"Number of recovery errors on the GPU NVLink port",
+			[]string{"gpu", "port"}, labels,
+		),
+		GPUNVLinkCRCErrors: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "nvlink_crc_errors_total"),
+			"Number of CRC errors on the GPU NVLink port, by type (flit or data)",
+			[]string{"gpu", "port", "type"}, labels,
+		),
+		GPUXGMISpeedGbps: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "xgmi_speed_gbps"),
+			"Current speed of the AMD GPU XGMI link in Gbit/s",
+			[]string{"id", "link"}, labels,
+		),
+		GPUXGMIReadBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "xgmi_read_bytes_total"),
+			"Number of bytes read over the AMD GPU XGMI link",
+			[]string{"id", "link"}, labels,
+		),
+		GPUXGMIWriteBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "xgmi_write_bytes_total"),
+			"Number of bytes written over the AMD GPU XGMI link",
+			[]string{"id", "link"}, labels,
+		),
+		GPUMIGInfo: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "mig_info"),
+			"Information about the MIG instance of the GPU",
+			[]string{"gpu", "instance", "profile", "uuid"}, labels,
+		),
+		GPUMIGMemoryTotalBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "mig_memory_total_bytes"),
+			"Total memory of the MIG instance of the GPU in bytes",
+			[]string{"gpu", "instance"}, labels,
+		),
+		GPUMIGBandwidthPercent: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "mig_bandwidth_percent"),
+			"Bandwidth utilization of the MIG instance of the GPU in percent",
+			[]string{"gpu", "instance"}, labels,
+		),
+		GPUMIGSMUtilizationPercent: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "mig_sm_utilization_percent"),
+			"SM utilization of the MIG instance of the GPU in percent",
+			[]string{"gpu", "instance"}, labels,
+		),
+		GPUPCIeLinkWidth: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "pcie_link_width"),
+			"Current number of lanes of the PCIe link of the GPU",
+			[]string{"id"}, labels,
+		),
+		GPUPCIeLinkMaxWidth: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "pcie_link_max_width"),
+			"Maximum number of lanes of the PCIe link of the GPU",
+			[]string{"id"}, labels,
+		),
+		GPUPCIeLinkGeneration: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "pcie_link_generation"),
+			"Current PCIe generation of the link of the GPU",
+			[]string{"id"}, labels,
+		),
+		GPUPCIeLinkMaxGeneration: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "pcie_link_max_generation"),
+			"Maximum PCIe generation of the link of the GPU",
+			[]string{"id"}, labels,
+		),
+		GPUPCIeLinkDegraded: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "pcie_link_degraded"),
+			"Whether the PCIe link of the GPU runs with fewer lanes than supported",
+			[]string{"id"}, labels,
+		),
+		GPUTemperatureThresholdCelsius: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "temperature_threshold_celsius"),
+			"Temperature threshold of the GPU in degrees Celsius, by threshold",
+			[]string{"id", "threshold"}, labels,
+		),
+		GPUTemperatureCelsius: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "temperature_celsius"),
+			"Temperature of the GPU in degrees Celsius, by the resource it is read from",
+			[]string{"id", "source"}, labels,
+		),
+		GPUTelemetryValue: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "telemetry_value"),
+			"Value of a GPU metric from a metric report of the telemetry service",
+			[]string{"id", "report", "metric"}, labels,
+		),
+		GPUEventsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "events_total"),
+			"Number of GPU related events received from the event service",
+			[]string{"id", "message_id", "severity"}, labels,
+		),
+		GPUEventMetricReportsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "event_metric_reports_total"),
+			"Number of metric reports received from the event service",
+			[]string{"report"}, labels,
+		),
+		GPUEventStreamConnected: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "event_stream_connected"),
+			"Whether the event stream of the BMC is connected",
+			nil, labels,
+		),
+		GPUEventMetricValue: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "event_metric_value"),
+			"Latest value of a GPU metric from a metric report received from the event service",
+			[]string{"id", "report", "metric"}, labels,
+		),
+		GPULogEventsTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "gpu", "log_events_total"),
+			"Number of GPU and PCIe related entries in the logs of the BMC",
+			[]string{"id", "severity", "message_id"}, labels,
+		),
+		ChassisInletTemperatureCelsius: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "chassis", "inlet_temperature_celsius"),
+			"Inlet temperature of the chassis in degrees Celsius",
+			nil, labels,
+		),
+		ChassisExhaustTemperatureCelsius: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "chassis", "exhaust_temperature_celsius"),
+			"Exhaust temperature of the chassis in degrees Celsius",
+			nil, labels,
+		),
+		ChassisFanSpeedRPM: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "chassis", "fan_speed_rpm"),
+			"Speed of the chassis fan in RPM",
+			[]string{"id", "name"}, labels,
+		),
+		ChassisFanSpeedPercent: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "chassis", "fan_speed_percent"),
+			"Speed of the chassis fan in percent of its maximum speed",
+			[]string{"id", "name"}, labels,
+		),
+		ChassisPSUInputPowerWatt: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "chassis", "psu_input_power_watt"),
+			"Input power of the power supply in watts",
+			[]string{"id"}, labels,
+		),
+		ChassisPSUOutputPowerWatt: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "chassis", "psu_output_power_watt"),
+			"Output power of the power supply in watts",
+			[]string{"id"}, labels,
+		),
+		ChassisConsumedPowerWatt: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "chassis", "consumed_power_watt"),
+			"Power consumed by the chassis in watts",
+			nil, labels,
+		),
+		ChassisPowerCapEnabled: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "chassis", "power_cap_enabled"),
+			"Whether power capping is enabled for the chassis",
+			nil, labels,
+		),
+		ChassisPowerCapWatt: prometheus.NewDesc(
+			prometheus.BuildFQName(prefix, "chassis", "power_cap_watt"),
+			"Power cap of the chassis in watts",
+			nil, labels,
 		),
 	}
 
-	collector.builder = new(strings.Builder)
-	collector.collected = sync.NewCond(new(sync.Mutex))
-	collector.registry = prometheus.NewRegistry()
-	collector.registry.MustRegister(collector)
-
 	return collector
 }
 
 func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- collector.ExporterBuildInfo
 	ch <- collector.ExporterScrapeErrorsTotal
+	ch <- collector.SystemInfo
+	ch <- collector.SystemPowerOn
+	ch <- collector.SystemHealth
 	ch <- collector.GPUInfo
 	ch <- collector.GPUHealth
+	ch <- collector.GPURedfishState
+	ch <- collector.GPURedfishHealth
+	ch <- collector.GPUSensorTemperatureCelsius
+	ch <- collector.GPUSensorPowerWatt
+	ch <- collector.GPUSensorHealth
 	ch <- collector.GPUState
 	ch <- collector.GPUBoardPowerSupplyStatus
 	ch <- collector.GPUMemoryTemperatureCelsius
@@ -239,29 +651,131 @@ func (collector *Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- collector.GPUMaxSupportedPCIeLinkSpeed
 	ch <- collector.GPUDRAMUtilizationPercent
 	ch <- collector.GPUPCIeCorrectableErrorCount
+	ch <- collector.GPUPCIeErrors
+	ch <- collector.GPUEnergyJoulesTotal
+	ch <- collector.GPUHostEnergyJoulesTotal
+	ch <- collector.GPUInventoryChangesTotal
+	ch <- collector.GPUInfoLastChangeTimestamp
+	ch <- collector.GPUMemoryCorrectableECCErrors
+	ch <- collector.GPUMemoryUncorrectableECCErrors
+	ch <- collector.GPUMemoryCorrectableRowRemaps
+	ch <- collector.GPUMemoryUncorrectableRowRemaps
+	ch <- collector.GPUMemoryRowRemappingPending
+	ch <- collector.GPUMemoryRowRemappingFailed
+	ch <- collector.GPUMemoryTotalBytes
+	ch <- collector.GPUECCModeEnabled
+	ch <- collector.GPUFirmwareInfo
+	ch <- collector.GPUTDPWatt
+	ch <- collector.GPUPowerLimitWatt
+	ch <- collector.GPUPowerHeadroomWatt
+	ch <- collector.GPUNVLinkStatus
+	ch <- collector.GPUNVLinkSpeedGbps
+	ch <- collector.GPUNVLinkTransmitBytes
+	ch <- collector.GPUNVLinkReceiveBytes
+	ch <- collector.GPUNVLinkReplayErrors
+	ch <- collector.GPUNVLinkRecoveryErrors
+	ch <- collector.GPUNVLinkCRCErrors
+	ch <- collector.GPUXGMISpeedGbps
+	ch <- collector.GPUXGMIReadBytes
+	ch <- collector.GPUXGMIWriteBytes
+	ch <- collector.GPUMIGInfo
+	ch <- collector.GPUMIGMemoryTotalBytes
+	ch <- collector.GPUMIGBandwidthPercent
+	ch <- collector.GPUMIGSMUtilizationPercent
+	ch <- collector.GPUPCIeLinkWidth
+	ch <- collector.GPUPCIeLinkMaxWidth
+	ch <- collector.GPUPCIeLinkGeneration
+	ch <- collector.GPUPCIeLinkMaxGeneration
+	ch <- collector.GPUPCIeLinkDegraded
+	ch <- collector.GPUTemperatureThresholdCelsius
+	ch <- collector.GPUTemperatureCelsius
+	ch <- collector.GPUTelemetryValue
+	ch <- collector.GPUEventsTotal
+	ch <- collector.GPUEventMetricReportsTotal
+	ch <- collector.GPUEventStreamConnected
+	ch <- collector.GPUEventMetricValue
+	ch <- collector.GPULogEventsTotal
+	ch <- collector.ChassisInletTemperatureCelsius
+	ch <- collector.ChassisExhaustTemperatureCelsius
+	ch <- collector.ChassisFanSpeedRPM
+	ch <- collector.ChassisFanSpeedPercent
+	ch <- collector.ChassisPSUInputPowerWatt
+	ch <- collector.ChassisPSUOutputPowerWatt
+	ch <- collector.ChassisConsumedPowerWatt
+	ch <- collector.ChassisPowerCapEnabled
+	ch <- collector.ChassisPowerCapWatt
 }
 
 func (collector *Collector) Collect(ch chan<- prometheus.Metric) {
 	collector.client.redfish.RefreshSession()
 
-	ok := collector.client.RefreshGPUs(collector, ch)
+	ok := collector.client.RefreshSystem(collector, ch)
+	if !ok {
+		collector.errors.Add(1)
+	}
+
+	// The metric reports of the telemetry service replace reading the
+	// processor metrics of each GPU, unless they are not available
+	var telemetry map[string]*GPUMetrics
+	if config.Config.Telemetry.Enabled {
+		telemetry = collector.client.RefreshTelemetry(collector, ch)
+	}
+
+	ok = collector.client.RefreshGPUs(collector, ch, telemetry)
 	if !ok {
 		collector.errors.Add(1)
 	}
 
+	// The chassis is read for the power cap of the system even when the
+	// chassis metrics are disabled
+	ok = collector.client.RefreshChassis(collector, ch)
+	if !ok && config.Config.Metrics.Chassis {
+		collector.errors.Add(1)
+	}
+
+	if config.Config.Metrics.Logs {
+		ok = collector.client.RefreshLogs(collector, ch)
+		if !ok {
+			collector.errors.Add(1)
+		}
+	}
+
+	if config.Config.Events.Enabled {
+		collector.events.Collect(collector, ch)
+	}
+
+	collector.energy.Save()
+	collector.inventory.Save()
+	collector.counters.Save()
+	collector.logs.Save()
+
 	ch <- prometheus.MustNewConstMetric(collector.ExporterBuildInfo, prometheus.UntypedValue, 1)
 	ch <- prometheus.MustNewConstMetric(collector.ExporterScrapeErrorsTotal, prometheus.CounterValue, float64(collector.errors.Load()))
 }
 
 func (collector *Collector) Gather() (string, error) {
+	_, metrics, err := collector.gather()
+	return metrics, err
+}
+
+// GatherFamilies is like Gather, but returns the collected metric families
+// instead of the text exposition. The returned families are shared with
+// concurrent callers and must not be modified.
+func (collector *Collector) GatherFamilies() ([]*dto.MetricFamily, error) {
+	families, _, err := collector.gather()
+	return families, err
+}
+
+func (collector *Collector) gather() ([]*dto.MetricFamily, string, error) {
 	collector.collected.L.Lock()
 
 	// If a collection is already in progress wait for it to complete and return the cached data
 	if collector.collecting {
 		collector.collected.Wait()
+		families := collector.families
 		metrics := collector.builder.String()
 		collector.collected.L.Unlock()
-		return metrics, nil
+		return families, metrics, nil
 	}
 
 	// Set collecting to true and let other goroutines enter in critical section
@@ -278,10 +792,11 @@ func (collector *Collector) Gather() (string, error) {
 
 	// Collect metrics
 	collector.builder.Reset()
+	collector.families = nil
 
 	m, err := collector.registry.Gather()
 	if err != nil {
-		return "", err
+		return nil, "", err
 	}
 
 	for i := range m {
@@ -291,27 +806,47 @@ func (collector *Collector) Gather() (string, error) {
 		}
 	}
 
-	return collector.builder.String(), nil
+	collector.families = m
+	return m, collector.builder.String(), nil
 }
 
 // Resets an existing collector of the given target
 func Reset(target string) {
 	mu.Lock()
-	_, ok := collectors[target]
+	collector, ok := collectors[target]
 	if ok {
 		delete(collectors, target)
 	}
 	mu.Unlock()
+
+	if ok {
+		collector.events.Stop()
+	}
 }
 
 func GetCollector(target string) (*Collector, error) {
 	mu.Lock()
 	collector, ok := collectors[target]
+	mu.Unlock()
+
 	if !ok {
-		collector = NewCollector()
-		collectors[target] = collector
+		host := config.GetHostConfig(target)
+		if host == nil {
+			return nil, fmt.Errorf("failed to get host information")
+		}
+		c, err := NewCollector(target, host.Labels)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create collector: %v", err)
+		}
+
+		mu.Lock()
+		collector, ok = collectors[target]
+		if !ok {
+			collector = c
+			collectors[target] = collector
+		}
+		mu.Unlock()
 	}
-	mu.Unlock()
 
 	// Do not act concurrently on the same host
 	collector.collected.L.Lock()
@@ -328,6 +863,9 @@ func GetCollector(target string) (*Collector, error) {
 			return nil, fmt.Errorf("failed to instantiate new client")
 		} else {
 			collector.client = c
+			if config.Config.Events.Enabled {
+				collector.events.Start(target, c)
+			}
 		}
 	}
 
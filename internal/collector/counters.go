@@ -0,0 +1,82 @@
+package collector
+
+import (
+	"sync"
+)
+
+// counterTracker keeps the counters read from the BMC of a single target
+// monotonic. Counters kept by the BMC start over when it reboots, which would
+// look like a negative rate, so the last value before a reset is added to the
+// values read after it.
+type counterTracker struct {
+	mu    sync.Mutex
+	path  string
+	state counterState
+}
+
+type counterState struct {
+	GPUs map[string]*gpuCounters `json:"gpus"`
+}
+
+type gpuCounters struct {
+	SerialNumber string                   `json:"serial_number"`
+	Counters     map[string]*trackedCount `json:"counters"`
+}
+
+type trackedCount struct {
+	Last   float64 `json:"last"`
+	Offset float64 `json:"offset"`
+}
+
+func newCounterTracker(target string) *counterTracker {
+	t := &counterTracker{
+		path: stateFile(target, "counters"),
+	}
+
+	loadState(t.path, &t.state)
+	if t.state.GPUs == nil {
+		t.state.GPUs = map[string]*gpuCounters{}
+	}
+
+	return t
+}
+
+// Update records the value of the named counter of the GPU as read from the
+// BMC and returns the counter with the resets of the BMC accounted for. The
+// counters of a GPU start over when its serial number changes, an empty
+// serial number is considered unknown.
+func (t *counterTracker) Update(id, serial, name string, v float64) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	g, ok := t.state.GPUs[id]
+	if ok && g.SerialNumber == "" {
+		g.SerialNumber = serial
+	}
+	if !ok || (serial != "" && g.SerialNumber != serial) {
+		g = &gpuCounters{
+			SerialNumber: serial,
+			Counters:     map[string]*trackedCount{},
+		}
+		t.state.GPUs[id] = g
+	}
+
+	c, ok := g.Counters[name]
+	if !ok {
+		c = &trackedCount{}
+		g.Counters[name] = c
+	} else if v < c.Last {
+		c.Offset += c.Last
+	}
+	c.Last = v
+
+	return c.Offset + v
+}
+
+// Save writes the counters to the state file, if persistence is enabled.
+func (t *counterTracker) Save() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	saveState(t.path, &t.state)
+}
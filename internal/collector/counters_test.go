@@ -0,0 +1,127 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+)
+
+type counterReading struct {
+	serial string
+	value  float64
+	want   float64
+}
+
+func TestCounterTrackerUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		readings []counterReading
+	}{
+		{"increasing", []counterReading{
+			{"A", 0, 0},
+			{"A", 5, 5},
+			{"A", 5, 5},
+			{"A", 8, 8},
+		}},
+		{"reset", []counterReading{
+			{"A", 10, 10},
+			{"A", 2, 12},
+			{"A", 3, 13},
+		}},
+		{"reset to zero", []counterReading{
+			{"A", 10, 10},
+			{"A", 0, 10},
+			{"A", 0, 10},
+			{"A", 4, 14},
+		}},
+		{"several resets", []counterReading{
+			{"A", 10, 10},
+			{"A", 4, 14},
+			{"A", 6, 16},
+			{"A", 1, 17},
+		}},
+		{"reset then regrow", []counterReading{
+			{"A", 2, 2},
+			{"A", 0, 2},
+			{"A", 3, 5},
+		}},
+		{"regrow past the last value", []counterReading{
+			{"A", 10, 10},
+			{"A", 0, 10},
+			{"A", 10, 20},
+			{"A", 11, 21},
+		}},
+		{"reset after reset", []counterReading{
+			{"A", 10, 10},
+			{"A", 2, 12},
+			{"A", 5, 15},
+			{"A", 0, 15},
+			{"A", 6, 21},
+		}},
+		{"serial change", []counterReading{
+			{"A", 10, 10},
+			{"A", 2, 12},
+			{"B", 3, 3},
+			{"B", 1, 4},
+		}},
+		{"unknown serial", []counterReading{
+			{"", 10, 10},
+			{"A", 2, 12},
+			{"", 3, 13},
+		}},
+	}
+
+	config.Config = config.NewConfig()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCounterTracker("bmc-1")
+
+			prev := 0.0
+			for i, r := range tt.readings {
+				got := c.Update("GPU1", r.serial, "fatal", r.value)
+				if got != r.want {
+					t.Errorf("reading %d: Update = %v, want %v", i, got, r.want)
+				}
+				if r.serial == tt.readings[0].serial && got < prev {
+					t.Errorf("reading %d: counter decreased from %v to %v", i, prev, got)
+				}
+				prev = got
+			}
+		})
+	}
+}
+
+func TestCounterTrackerNames(t *testing.T) {
+	config.Config = config.NewConfig()
+
+	c := newCounterTracker("bmc-1")
+	c.Update("GPU1", "A", "fatal", 10)
+	c.Update("GPU1", "A", "replay", 3)
+	c.Update("GPU2", "B", "fatal", 1)
+
+	if got := c.Update("GPU1", "A", "fatal", 1); got != 11 {
+		t.Errorf("fatal = %v, want 11", got)
+	}
+	if got := c.Update("GPU1", "A", "replay", 4); got != 4 {
+		t.Errorf("replay = %v, want 4", got)
+	}
+	if got := c.Update("GPU2", "B", "fatal", 2); got != 2 {
+		t.Errorf("fatal of GPU2 = %v, want 2", got)
+	}
+}
+
+func TestCounterTrackerState(t *testing.T) {
+	config.Config = config.NewConfig()
+	config.Config.StateDir = t.TempDir()
+
+	c := newCounterTracker("bmc-1")
+	c.Update("GPU1", "A", "fatal", 10)
+	c.Update("GPU1", "A", "fatal", 2)
+	c.Save()
+
+	c = newCounterTracker("bmc-1")
+	if got := c.Update("GPU1", "A", "fatal", 3); got != 13 {
+		t.Errorf("Update = %v, want 13", got)
+	}
+}
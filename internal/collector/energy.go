@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+// Power readings further apart than this are not integrated, since nothing is
+// known about the power draw in between (e.g. while the exporter was down).
+const maxEnergyInterval = 10 * time.Minute
+
+// energyMeter integrates the power readings of the GPUs of a single target
+// into energy counters, using the trapezoidal rule between two readings.
+type energyMeter struct {
+	mu    sync.Mutex
+	path  string
+	state energyState
+}
+
+type energyState struct {
+	HostJoules float64               `json:"host_joules"`
+	GPUs       map[string]*gpuEnergy `json:"gpus"`
+}
+
+type gpuEnergy struct {
+	SerialNumber string    `json:"serial_number"`
+	Joules       float64   `json:"joules"`
+	Watts        float64   `json:"watts"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+func newEnergyMeter(target string) *energyMeter {
+	e := &energyMeter{
+		path: stateFile(target, "energy"),
+	}
+
+	loadState(e.path, &e.state)
+	if e.state.GPUs == nil {
+		e.state.GPUs = map[string]*gpuEnergy{}
+	}
+
+	return e
+}
+
+// Update adds the energy consumed by the GPU since its previous reading and
+// returns the energy counter of the GPU in joules. The counter starts over
+// when the serial number of the GPU changes, an empty serial number is
+// considered unknown.
+func (e *energyMeter) Update(id, serial string, watts float64, now time.Time) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	g, ok := e.state.GPUs[id]
+	if ok && g.SerialNumber == "" {
+		g.SerialNumber = serial
+	}
+	if !ok || (serial != "" && g.SerialNumber != serial) {
+		g = &gpuEnergy{SerialNumber: serial}
+		e.state.GPUs[id] = g
+	} else {
+		dt := now.Sub(g.Timestamp)
+		if dt > 0 && dt <= maxEnergyInterval {
+			joules := (g.Watts + watts) / 2 * dt.Seconds()
+			g.Joules += joules
+			e.state.HostJoules += joules
+		}
+	}
+
+	g.Watts = watts
+	g.Timestamp = now
+
+	return g.Joules
+}
+
+// HostJoules returns the energy consumed by all GPUs of the target in joules.
+func (e *energyMeter) HostJoules() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.state.HostJoules
+}
+
+// Save writes the energy counters to the state file, if persistence is enabled.
+func (e *energyMeter) Save() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	saveState(e.path, &e.state)
+}
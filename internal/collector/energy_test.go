@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+)
+
+type energyReading struct {
+	serial string
+	watts  float64
+	offset time.Duration
+}
+
+func TestEnergyMeterUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		readings []energyReading
+		want     float64
+	}{
+		{"first reading", []energyReading{
+			{"A", 300, 0},
+		}, 0},
+		{"trapezoid", []energyReading{
+			{"A", 300, 0},
+			{"A", 500, 10 * time.Second},
+			{"A", 500, 20 * time.Second},
+		}, 4000 + 5000},
+		{"maximum interval", []energyReading{
+			{"A", 100, 0},
+			{"A", 100, maxEnergyInterval},
+		}, 100 * maxEnergyInterval.Seconds()},
+		{"gap", []energyReading{
+			{"A", 300, 0},
+			{"A", 500, 10 * time.Second},
+			{"A", 200, 10*time.Second + maxEnergyInterval + time.Second},
+			{"A", 400, 20*time.Second + maxEnergyInterval + time.Second},
+		}, 4000 + 3000},
+		{"clock going back", []energyReading{
+			{"A", 300, 10 * time.Second},
+			{"A", 300, 0},
+		}, 0},
+		{"serial change", []energyReading{
+			{"A", 300, 0},
+			{"A", 300, 10 * time.Second},
+			{"B", 100, 20 * time.Second},
+			{"B", 300, 30 * time.Second},
+		}, 2000},
+		{"unknown serial", []energyReading{
+			{"", 300, 0},
+			{"A", 300, 10 * time.Second},
+			{"", 300, 20 * time.Second},
+			{"A", 300, 30 * time.Second},
+		}, 9000},
+	}
+
+	config.Config = config.NewConfig()
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnergyMeter("bmc-1")
+
+			var got float64
+			for _, r := range tt.readings {
+				got = e.Update("GPU1", r.serial, r.watts, start.Add(r.offset))
+			}
+			if got != tt.want {
+				t.Errorf("Update = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnergyMeterHostJoules(t *testing.T) {
+	config.Config = config.NewConfig()
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	e := newEnergyMeter("bmc-1")
+	e.Update("GPU1", "A", 100, start)
+	e.Update("GPU2", "B", 200, start)
+	e.Update("GPU1", "A", 100, start.Add(10*time.Second))
+	e.Update("GPU2", "B", 200, start.Add(10*time.Second))
+
+	// A replaced GPU does not decrease the energy of the host
+	e.Update("GPU2", "C", 200, start.Add(20*time.Second))
+
+	if got := e.HostJoules(); got != 3000 {
+		t.Errorf("HostJoules = %v, want 3000", got)
+	}
+}
+
+func TestEnergyMeterState(t *testing.T) {
+	config.Config = config.NewConfig()
+	config.Config.StateDir = t.TempDir()
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	e := newEnergyMeter("bmc-1:443")
+	e.Update("GPU1", "A", 100, start)
+	e.Update("GPU1", "A", 100, start.Add(10*time.Second))
+	e.Save()
+
+	// The counters continue from the saved state
+	e = newEnergyMeter("bmc-1:443")
+	if got := e.Update("GPU1", "A", 300, start.Add(20*time.Second)); got != 1000+2000 {
+		t.Errorf("Update = %v, want 3000", got)
+	}
+	if got := e.HostJoules(); got != 3000 {
+		t.Errorf("HostJoules = %v, want 3000", got)
+	}
+
+	// The state of other targets is separate
+	e = newEnergyMeter("bmc-2")
+	if got := e.Update("GPU1", "A", 100, start.Add(30*time.Second)); got != 0 {
+		t.Errorf("Update of another target = %v, want 0", got)
+	}
+}
@@ -0,0 +1,494 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+	"github.com/smc-public/idrac_gpu_exporter/internal/log"
+)
+
+const (
+	// eventRetryInterval is the time to wait before reconnecting the event
+	// stream or subscribing again
+	eventRetryInterval = 30 * time.Second
+
+	// recentEventsSize is the number of events kept for the events endpoint
+	recentEventsSize = 100
+)
+
+// RecentEvent is a GPU related event received from the event service.
+type RecentEvent struct {
+	Received  time.Time `json:"received"`
+	Timestamp string    `json:"timestamp,omitempty"`
+	Id        string    `json:"id"`
+	MessageId string    `json:"message_id"`
+	Severity  string    `json:"severity"`
+	Message   string    `json:"message,omitempty"`
+	Origin    string    `json:"origin,omitempty"`
+}
+
+type eventValueKey struct {
+	id     string
+	report string
+	metric string
+}
+
+type eventKey struct {
+	id        string
+	messageId string
+	severity  string
+}
+
+// eventLog counts the GPU related events and metric reports received from
+// the event service of a single target, either streamed from the BMC or
+// pushed to the exporter.
+type eventLog struct {
+	mu           sync.Mutex
+	counts       map[eventKey]uint64
+	reports      map[string]uint64
+	values       map[eventValueKey]*telemetrySample
+	recent       []RecentEvent
+	connected    bool
+	cancel       context.CancelFunc
+	done         chan struct{}
+	subscription string
+	redfish      *Redfish
+}
+
+func newEventLog() *eventLog {
+	return &eventLog{
+		counts:  map[eventKey]uint64{},
+		reports: map[string]uint64{},
+		values:  map[eventValueKey]*telemetrySample{},
+	}
+}
+
+// Start receives the events of the target in the background, in the mode
+// given by the configuration. The events are received with a copy of the
+// Redfish host of the client, since the session of the client is refreshed
+// by the scrapes.
+func (e *eventLog) Start(target string, client *Client) {
+	if client.eventPath == "" {
+		log.Info("Event service not available for %s", target)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	redfish := client.redfish.withoutSession()
+
+	e.mu.Lock()
+	e.cancel = cancel
+	e.done = done
+	e.redfish = redfish
+	e.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		if config.Config.Events.Mode == "push" {
+			e.subscribe(ctx, target, redfish, client.eventPath)
+		} else {
+			e.stream(ctx, target, redfish, client.eventPath)
+		}
+	}()
+}
+
+// Stop stops receiving the events and removes the push subscription. It
+// waits for a subscription in progress, so it is removed as well.
+func (e *eventLog) Stop() {
+	e.mu.Lock()
+	cancel := e.cancel
+	done := e.done
+	e.cancel = nil
+	e.done = nil
+	e.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	e.mu.Lock()
+	subscription := e.subscription
+	redfish := e.redfish
+	e.subscription = ""
+	e.mu.Unlock()
+
+	if subscription != "" && redfish != nil {
+		resp, err := redfish.EventRequest(context.Background(), http.MethodDelete, subscription, nil)
+		if err != nil {
+			log.Error("Error removing event subscription %s: %v", subscription, err)
+			return
+		}
+		resp.Body.Close()
+	}
+}
+
+// stream reads the events of the target from the server sent event stream
+// of the event service, reconnecting when the stream ends.
+func (e *eventLog) stream(ctx context.Context, target string, redfish *Redfish, path string) {
+	service := EventService{}
+	for ctx.Err() == nil {
+		if ok := redfish.Get(path, &service); ok && service.ServerSentEventUri != "" {
+			break
+		}
+		log.Info("Event stream not available for %s, retrying in %v", target, eventRetryInterval)
+		if !sleepContext(ctx, eventRetryInterval) {
+			return
+		}
+	}
+
+	for ctx.Err() == nil {
+		resp, err := redfish.EventRequest(ctx, http.MethodGet, service.ServerSentEventUri, nil)
+		if err != nil {
+			log.Error("Error connecting event stream of %s: %v", target, err)
+		} else if resp.StatusCode != http.StatusOK {
+			log.Error("Error connecting event stream of %s: %s", target, resp.Status)
+			resp.Body.Close()
+		} else {
+			log.Info("Connected event stream of %s", target)
+			e.setConnected(true)
+			err = readEventStream(resp, e.Handle)
+			e.setConnected(false)
+			resp.Body.Close()
+			if ctx.Err() != nil {
+				return
+			}
+			log.Info("Event stream of %s closed: %v", target, err)
+		}
+
+		if !sleepContext(ctx, eventRetryInterval) {
+			return
+		}
+	}
+}
+
+// readEventStream calls handle with the data of each event of the stream
+// until the stream ends.
+func readEventStream(resp *http.Response, handle func([]byte)) error {
+	var data []byte
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if len(data) > 0 {
+				handle(data)
+				data = nil
+			}
+			continue
+		}
+
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			if len(data) > 0 {
+				data = append(data, '\n')
+			}
+			data = append(data, strings.TrimPrefix(value, " ")...)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// subscribe registers the push URL with the event service of the target,
+// using the target as context, so the pushed events can be matched to it.
+// A subscription of the push URL that is left over, e.g. when the exporter
+// was killed, is used again, so the subscriptions do not pile up on the BMC.
+func (e *eventLog) subscribe(ctx context.Context, target string, redfish *Redfish, path string) {
+	destination, err := url.Parse(config.Config.Events.PushURL)
+	if err != nil {
+		log.Error("Invalid push URL for the events of %s: %v", target, err)
+		return
+	}
+
+	// The token authenticates the pushed events
+	query := destination.Query()
+	query.Set("token", config.Config.Events.PushToken)
+	destination.RawQuery = query.Encode()
+
+	service := EventService{}
+	subscription := EventSubscription{
+		Destination: destination.String(),
+		Context:     target,
+		Protocol:    "Redfish",
+	}
+
+	for ctx.Err() == nil {
+		if ok := redfish.Get(path, &service); ok && service.Subscriptions.OdataId != "" {
+			if location := existingSubscription(ctx, redfish, service.Subscriptions.OdataId, &subscription); location != "" {
+				log.Info("Using the existing subscription of %s to the events of %s", config.Config.Events.PushURL, target)
+				e.mu.Lock()
+				e.subscription = location
+				e.mu.Unlock()
+				return
+			}
+
+			resp, err := redfish.EventRequest(ctx, http.MethodPost, service.Subscriptions.OdataId, &subscription)
+			if err == nil {
+				resp.Body.Close()
+				location := resp.Header.Get("Location")
+				if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusOK {
+					if _, path, ok := strings.Cut(location, "/redfish/"); ok {
+						location = "/redfish/" + path
+					}
+					log.Info("Subscribed %s to the events of %s", config.Config.Events.PushURL, target)
+					e.mu.Lock()
+					e.subscription = location
+					e.mu.Unlock()
+					return
+				}
+				log.Error("Error subscribing to the events of %s: %s", target, resp.Status)
+			} else {
+				log.Error("Error subscribing to the events of %s: %v", target, err)
+			}
+		}
+
+		if !sleepContext(ctx, eventRetryInterval) {
+			return
+		}
+	}
+}
+
+// existingSubscription returns the subscription of the event service that
+// matches the destination and context of the subscription. Other
+// subscriptions of the same context and push URL, e.g. with an old token,
+// are removed.
+func existingSubscription(ctx context.Context, redfish *Redfish, path string, subscription *EventSubscription) string {
+	collection := GroupResponse{}
+	if ok := redfish.Get(path, &collection); !ok {
+		return ""
+	}
+
+	found := ""
+	for _, link := range collection.Members.GetLinks() {
+		existing := EventSubscription{}
+		if ok := redfish.Get(link, &existing); !ok || existing.Context != subscription.Context || !sameDestination(existing.Destination, subscription.Destination) {
+			continue
+		}
+
+		if found == "" && existing.Destination == subscription.Destination {
+			found = link
+			continue
+		}
+
+		resp, err := redfish.EventRequest(ctx, http.MethodDelete, link, nil)
+		if err != nil {
+			log.Error("Error removing event subscription %s: %v", link, err)
+			continue
+		}
+		resp.Body.Close()
+	}
+
+	return found
+}
+
+// sameDestination reports whether the destinations are the same URL, apart
+// from the query.
+func sameDestination(a, b string) bool {
+	u, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	v, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(u.Scheme, v.Scheme) && strings.EqualFold(u.Host, v.Host) && u.Path == v.Path
+}
+
+// Handle decodes an event or metric report received from the event service
+// and counts it, when it relates to a GPU.
+func (e *eventLog) Handle(data []byte) {
+	message := EventMessage{}
+	if err := json.Unmarshal(data, &message); err != nil {
+		log.Debug("Ignoring invalid event: %v", err)
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if message.MetricValues != nil {
+		e.reports[message.Id]++
+
+		// Keep the latest value of every metric of the GPUs in the report
+		samples := map[string]*telemetrySample{}
+		reportTime, _ := time.Parse(time.RFC3339, message.Timestamp)
+		for i := range message.MetricValues {
+			addTelemetrySample(samples, message.Id, &message.MetricValues[i], reportTime)
+		}
+		for _, s := range samples {
+			k := eventValueKey{s.id, s.report, s.metric}
+			if prev, ok := e.values[k]; ok && prev.timestamp.After(s.timestamp) {
+				continue
+			}
+			e.values[k] = s
+		}
+		return
+	}
+
+	now := time.Now()
+	for i := range message.Events {
+		r := &message.Events[i]
+		id := eventGPU(r)
+		if id == "" {
+			continue
+		}
+
+		severity := r.MessageSeverity
+		if severity == "" {
+			severity = r.Severity
+		}
+
+		e.counts[eventKey{id, r.MessageId, severity}]++
+		e.recent = append(e.recent, RecentEvent{
+			Received:  now,
+			Timestamp: r.EventTimestamp,
+			Id:        id,
+			MessageId: r.MessageId,
+			Severity:  severity,
+			Message:   r.Message,
+			Origin:    r.OriginOfCondition.OdataId,
+		})
+		if len(e.recent) > recentEventsSize {
+			e.recent = e.recent[len(e.recent)-recentEventsSize:]
+		}
+	}
+}
+
+// Collect emits the event counters.
+func (e *eventLog) Collect(mc *Collector, ch chan<- prometheus.Metric) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for k, v := range e.counts {
+		ch <- prometheus.MustNewConstMetric(mc.GPUEventsTotal, prometheus.CounterValue, float64(v), k.id, k.messageId, k.severity)
+	}
+	for k, v := range e.reports {
+		ch <- prometheus.MustNewConstMetric(mc.GPUEventMetricReportsTotal, prometheus.CounterValue, float64(v), k)
+	}
+	for _, s := range e.values {
+		m := prometheus.MustNewConstMetric(mc.GPUEventMetricValue, prometheus.GaugeValue, s.value, s.id, s.report, s.metric)
+		if !s.timestamp.IsZero() {
+			m = prometheus.NewMetricWithTimestamp(s.timestamp, m)
+		}
+		ch <- m
+	}
+	if config.Config.Events.Mode != "push" {
+		ch <- prometheus.MustNewConstMetric(mc.GPUEventStreamConnected, prometheus.GaugeValue, bool2value(e.connected))
+	}
+}
+
+// Recent returns the most recent GPU related events, newest first.
+func (e *eventLog) Recent() []RecentEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	events := make([]RecentEvent, 0, len(e.recent))
+	for i := len(e.recent) - 1; i >= 0; i-- {
+		events = append(events, e.recent[i])
+	}
+
+	return events
+}
+
+func (e *eventLog) setConnected(connected bool) {
+	e.mu.Lock()
+	e.connected = connected
+	e.mu.Unlock()
+}
+
+// eventGPU returns the id of the GPU an event relates to.
+func eventGPU(r *EventRecord) string {
+	return gpuReference(r.OriginOfCondition.OdataId, r.MessageArgs)
+}
+
+// gpuReference returns the id of the GPU an event or log entry relates to,
+// taken from the processor it originates from or from the Dell FQDD in its
+// arguments.
+func gpuReference(origin string, args []string) string {
+	if _, rest, ok := strings.Cut(origin, "/Processors/"); ok {
+		id, _, _ := strings.Cut(rest, "/")
+		if id != "" && !strings.HasPrefix(strings.ToUpper(id), "CPU") {
+			return id
+		}
+	}
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "Video.") {
+			return arg
+		}
+	}
+	return ""
+}
+
+// sleepContext waits for the duration and returns false when the context is
+// canceled before.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// Shutdown stops receiving the events of all targets, which removes the push
+// subscriptions from the BMCs.
+func Shutdown() {
+	mu.Lock()
+	all := make([]*Collector, 0, len(collectors))
+	for _, c := range collectors {
+		all = append(all, c)
+	}
+	mu.Unlock()
+
+	for _, c := range all {
+		c.events.Stop()
+	}
+}
+
+// GetEvents returns the most recent GPU related events of the target.
+func GetEvents(target string) []RecentEvent {
+	mu.Lock()
+	collector, ok := collectors[target]
+	mu.Unlock()
+
+	if !ok {
+		return []RecentEvent{}
+	}
+
+	return collector.events.Recent()
+}
+
+// PushEvent handles an event pushed by the event service of a target, which
+// is identified by the context of the subscription. It returns false when
+// the target is unknown.
+func PushEvent(data []byte) bool {
+	message := EventMessage{}
+	if err := json.Unmarshal(data, &message); err != nil {
+		return false
+	}
+
+	mu.Lock()
+	collector, ok := collectors[message.Context]
+	mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	collector.events.Handle(data)
+	return true
+}
@@ -0,0 +1,255 @@
+package collector
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+)
+
+func TestReadEventStream(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		want   []string
+	}{
+		{"single line", "data: {\"a\":1}\n\n", []string{`{"a":1}`}},
+		{"multiple lines", "data: {\"a\":\ndata: 1}\n\n", []string{"{\"a\":\n1}"}},
+		{"no space", "data:{}\n\n", []string{"{}"}},
+		{"several events", "data: 1\n\ndata: 2\n\n", []string{"1", "2"}},
+		{"other fields", ": keep-alive\nid: 7\nevent: message\ndata: 1\n\n", []string{"1"}},
+		{"repeated blank lines", "\n\ndata: 1\n\n\n\ndata: 2\n\n", []string{"1", "2"}},
+		{"unterminated", "data: 1\n\ndata: 2\n", []string{"1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Body: io.NopCloser(strings.NewReader(tt.stream))}
+
+			got := []string{}
+			err := readEventStream(resp, func(data []byte) {
+				got = append(got, string(data))
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventLogHandle(t *testing.T) {
+	config.Config = config.NewConfig()
+	config.Config.Events.Mode = "push"
+	mc := newDescriptors("idrac", nil)
+
+	e := newEventLog()
+	e.Handle([]byte(`{"Events":[
+		{"MessageId":"GPU0001","MessageSeverity":"Critical","OriginOfCondition":{"@odata.id":"/redfish/v1/Systems/1/Processors/GPU1"}},
+		{"MessageId":"GPU0001","MessageSeverity":"Critical","OriginOfCondition":{"@odata.id":"/redfish/v1/Systems/1/Processors/GPU1"}},
+		{"MessageId":"PDR1016","Severity":"Warning","MessageArgs":["Video.Slot.21-1"]},
+		{"MessageId":"CPU0001","MessageSeverity":"Critical","OriginOfCondition":{"@odata.id":"/redfish/v1/Systems/1/Processors/CPU1"}},
+		{"MessageId":"PSU0003","MessageSeverity":"Critical"}
+	]}`))
+	e.Handle([]byte(`not json`))
+	e.Handle([]byte(`{"Id":"GPUMetrics","Timestamp":"2026-10-19T10:00:00Z","MetricValues":[
+		{"MetricId":"PowerConsumption","MetricValue":"410","Timestamp":"2026-10-19T09:59:50Z","Oem":{"Dell":{"FQDD":"Video.Slot.21-1"}}},
+		{"MetricId":"PowerConsumption","MetricValue":"415","Timestamp":"2026-10-19T10:00:00Z","Oem":{"Dell":{"FQDD":"Video.Slot.21-1"}}},
+		{"MetricId":"GPUUtilization","MetricValue":"78","MetricProperty":"/redfish/v1/Systems/1/Processors/GPU1/ProcessorMetrics#/Oem/Util"}
+	]}`))
+	// An older report does not replace the latest values
+	e.Handle([]byte(`{"Id":"GPUMetrics","Timestamp":"2026-10-19T09:00:00Z","MetricValues":[
+		{"MetricId":"PowerConsumption","MetricValue":"300","Oem":{"Dell":{"FQDD":"Video.Slot.21-1"}}}
+	]}`))
+
+	ch, wait := drain()
+	e.Collect(mc, ch)
+	metrics := wait()
+
+	events := map[string]float64{}
+	reports := map[string]float64{}
+	values := map[string]float64{}
+	for _, m := range metrics {
+		labels, v, pb := readMetric(t, m)
+		switch m.Desc() {
+		case mc.GPUEventsTotal:
+			events[labels["id"]+" "+labels["message_id"]+" "+labels["severity"]] = v
+		case mc.GPUEventMetricReportsTotal:
+			reports[labels["report"]] = v
+		case mc.GPUEventMetricValue:
+			values[labels["id"]+" "+labels["metric"]] = v
+			if labels["metric"] == "PowerConsumption" && pb.GetTimestampMs() != 1792404000000 {
+				t.Errorf("timestamp = %d, want the time of the latest reading", pb.GetTimestampMs())
+			}
+		default:
+			t.Errorf("unexpected metric %s", m.Desc())
+		}
+	}
+
+	wantEvents := map[string]float64{
+		"GPU1 GPU0001 Critical":           2,
+		"Video.Slot.21-1 PDR1016 Warning": 1,
+	}
+	if !reflect.DeepEqual(events, wantEvents) {
+		t.Errorf("events = %v, want %v", events, wantEvents)
+	}
+	if want := map[string]float64{"GPUMetrics": 2}; !reflect.DeepEqual(reports, want) {
+		t.Errorf("reports = %v, want %v", reports, want)
+	}
+	wantValues := map[string]float64{
+		"Video.Slot.21-1 PowerConsumption": 415,
+		"GPU1 GPUUtilization":              78,
+	}
+	if !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("values = %v, want %v", values, wantValues)
+	}
+
+	recent := e.Recent()
+	if len(recent) != 3 || recent[0].MessageId != "PDR1016" || recent[2].Id != "GPU1" {
+		t.Errorf("unexpected recent events %+v", recent)
+	}
+}
+
+func TestEventLogSubscribe(t *testing.T) {
+	const (
+		subscriptions = "/redfish/v1/EventService/Subscriptions"
+		destination   = "https://exporter.example.com:9348/events/push"
+	)
+
+	tests := []struct {
+		name     string
+		existing map[string]string
+		want     []string
+	}{
+		{
+			name: "new",
+			want: []string{"POST " + subscriptions, "DELETE " + subscriptions + "/9"},
+		},
+		{
+			name: "existing",
+			existing: map[string]string{
+				"1": `{"Destination":"` + destination + `?token=secret","Context":"bmc-1"}`,
+				"2": `{"Destination":"` + destination + `?token=old","Context":"bmc-1"}`,
+				"3": `{"Destination":"` + destination + `?token=secret","Context":"bmc-2"}`,
+				"4": `{"Destination":"https://other.example.com/events","Context":"bmc-1"}`,
+			},
+			want: []string{"DELETE " + subscriptions + "/2", "DELETE " + subscriptions + "/1"},
+		},
+		{
+			name: "stale",
+			existing: map[string]string{
+				"1": `{"Destination":"` + destination + `?token=old","Context":"bmc-1"}`,
+			},
+			want: []string{"DELETE " + subscriptions + "/1", "POST " + subscriptions, "DELETE " + subscriptions + "/9"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			requests := []string{}
+			client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet {
+					mu.Lock()
+					requests = append(requests, r.Method+" "+r.URL.Path)
+					mu.Unlock()
+				}
+
+				switch {
+				case r.URL.Path == "/redfish/v1/EventService":
+					_, _ = w.Write([]byte(`{"Subscriptions":{"@odata.id":"` + subscriptions + `"}}`))
+				case r.URL.Path == subscriptions && r.Method == http.MethodGet:
+					members := []string{}
+					for id := range tt.existing {
+						members = append(members, `{"@odata.id":"`+subscriptions+"/"+id+`"}`)
+					}
+					_, _ = w.Write([]byte(`{"Members":[` + strings.Join(members, ",") + `]}`))
+				case r.URL.Path == subscriptions && r.Method == http.MethodPost:
+					w.Header().Set("Location", "https://"+r.Host+subscriptions+"/9")
+					w.WriteHeader(http.StatusCreated)
+				case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, subscriptions+"/"):
+					_, _ = w.Write([]byte(tt.existing[strings.TrimPrefix(r.URL.Path, subscriptions+"/")]))
+				}
+			})
+			config.Config.Events.Mode = "push"
+			config.Config.Events.PushURL = destination
+			config.Config.Events.PushToken = "secret"
+			client.eventPath = "/redfish/v1/EventService"
+
+			e := newEventLog()
+			e.Start("bmc-1", client)
+			for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+				e.mu.Lock()
+				subscribed := e.subscription != ""
+				e.mu.Unlock()
+				if subscribed {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("not subscribed")
+				}
+			}
+			e.Stop()
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !reflect.DeepEqual(requests, tt.want) {
+				t.Errorf("requests = %q, want %q", requests, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventLogRecentSize(t *testing.T) {
+	e := newEventLog()
+	for i := 0; i < recentEventsSize+10; i++ {
+		e.Handle([]byte(`{"Events":[{"MessageId":"GPU0001","MessageArgs":["Video.Slot.21-1"]}]}`))
+	}
+
+	if n := len(e.Recent()); n != recentEventsSize {
+		t.Errorf("kept %d recent events, want %d", n, recentEventsSize)
+	}
+}
+
+func TestPushEvent(t *testing.T) {
+	collector := &Collector{events: newEventLog()}
+
+	mu.Lock()
+	collectors["bmc-1"] = collector
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		delete(collectors, "bmc-1")
+		mu.Unlock()
+	})
+
+	tests := []struct {
+		name string
+		data string
+		ok   bool
+	}{
+		{"known target", `{"Context":"bmc-1","Events":[{"MessageId":"GPU0001","MessageArgs":["Video.Slot.21-1"]}]}`, true},
+		{"unknown target", `{"Context":"bmc-2","Events":[{"MessageId":"GPU0001","MessageArgs":["Video.Slot.21-1"]}]}`, false},
+		{"no context", `{"Events":[{"MessageId":"GPU0001","MessageArgs":["Video.Slot.21-1"]}]}`, false},
+		{"invalid", `{"Context":`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ok := PushEvent([]byte(tt.data)); ok != tt.ok {
+				t.Errorf("PushEvent = %v, want %v", ok, tt.ok)
+			}
+		})
+	}
+
+	if n := len(collector.events.Recent()); n != 1 {
+		t.Errorf("handled %d events, want 1", n)
+	}
+}
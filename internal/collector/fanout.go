@@ -0,0 +1,144 @@
+package collector
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+	"github.com/smc-public/idrac_gpu_exporter/internal/log"
+)
+
+const targetLabel = "target"
+
+// GatherTargets collects metrics from all given targets concurrently, with at
+// most concurrency collections in flight, and returns them as one text
+// exposition where every series carries a "target" label. The outcome of each
+// collection is reported in the target_scrape_success metric.
+func GatherTargets(targets []string, concurrency int) (string, error) {
+	return gatherTargets(targets, concurrency, gatherTarget)
+}
+
+func gatherTargets(targets []string, concurrency int, gather func(string) ([]*dto.MetricFamily, error)) (string, error) {
+	results := make([][]*dto.MetricFamily, len(targets))
+	success := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: prometheus.BuildFQName(config.Config.MetricsPrefix, "gpu_exporter", "target_scrape_success"),
+			Help: "Whether the collection of metrics from the target succeeded",
+		},
+		[]string{targetLabel},
+	)
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			families, err := gather(target)
+			if err != nil {
+				log.Error("Error collecting metrics for host %s: %v", target, err)
+				success.WithLabelValues(target).Set(0)
+				return
+			}
+
+			results[i] = families
+			success.WithLabelValues(target).Set(1)
+		}(i, target)
+	}
+	wg.Wait()
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(success)
+	m, err := registry.Gather()
+	if err != nil {
+		return "", err
+	}
+
+	merged := map[string]*dto.MetricFamily{}
+	for _, mf := range m {
+		merged[mf.GetName()] = mf
+	}
+
+	for i, families := range results {
+		for _, mf := range families {
+			dst, ok := merged[mf.GetName()]
+			if !ok {
+				dst = &dto.MetricFamily{
+					Name: mf.Name,
+					Help: mf.Help,
+					Type: mf.Type,
+					Unit: mf.Unit,
+				}
+				merged[mf.GetName()] = dst
+			}
+			for _, metric := range mf.Metric {
+				dst.Metric = append(dst.Metric, withTargetLabel(metric, targets[i]))
+			}
+		}
+	}
+
+	names := make([]string, 0, len(merged))
+	for name := range merged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	builder := new(strings.Builder)
+	for _, name := range names {
+		_, err := expfmt.MetricFamilyToText(builder, merged[name])
+		if err != nil {
+			log.Error("Error converting metric to text: %v", err)
+		}
+	}
+
+	return builder.String(), nil
+}
+
+func gatherTarget(target string) ([]*dto.MetricFamily, error) {
+	c, err := GetCollector(target)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GatherFamilies()
+}
+
+// withTargetLabel returns a copy of the metric with the target label added,
+// since the original may be shared with other callers of the collector. A
+// label of the metric with the same name is replaced, so the series stay
+// valid.
+func withTargetLabel(m *dto.Metric, target string) *dto.Metric {
+	name := targetLabel
+	labels := make([]*dto.LabelPair, 0, len(m.Label)+1)
+	for _, l := range m.Label {
+		if l.GetName() != targetLabel {
+			labels = append(labels, l)
+		}
+	}
+	labels = append(labels, &dto.LabelPair{Name: &name, Value: &target})
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].GetName() < labels[j].GetName()
+	})
+
+	return &dto.Metric{
+		Label:       labels,
+		Gauge:       m.Gauge,
+		Counter:     m.Counter,
+		Summary:     m.Summary,
+		Untyped:     m.Untyped,
+		Histogram:   m.Histogram,
+		TimestampMs: m.TimestampMs,
+	}
+}
@@ -0,0 +1,153 @@
+package collector
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+)
+
+// powerFamily returns a gauge family with a single series, labeled with the
+// given label pairs
+func powerFamily(value float64, labels ...string) []*dto.MetricFamily {
+	name, help, typ := "idrac_gpu_power_watt", "Power of the GPU", dto.MetricType_GAUGE
+	metric := &dto.Metric{Gauge: &dto.Gauge{Value: &value}}
+	for i := 0; i+1 < len(labels); i += 2 {
+		metric.Label = append(metric.Label, &dto.LabelPair{Name: &labels[i], Value: &labels[i+1]})
+	}
+
+	return []*dto.MetricFamily{{Name: &name, Help: &help, Type: &typ, Metric: []*dto.Metric{metric}}}
+}
+
+func TestGatherTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		targets  []string
+		families map[string][]*dto.MetricFamily
+		want     string
+	}{
+		{
+			name:    "merge",
+			targets: []string{"bmc-1", "bmc-2"},
+			families: map[string][]*dto.MetricFamily{
+				"bmc-1": powerFamily(310, "id", "GPU1"),
+				"bmc-2": powerFamily(420, "id", "GPU1"),
+			},
+			want: `# HELP idrac_gpu_exporter_target_scrape_success Whether the collection of metrics from the target succeeded
+# TYPE idrac_gpu_exporter_target_scrape_success gauge
+idrac_gpu_exporter_target_scrape_success{target="bmc-1"} 1
+idrac_gpu_exporter_target_scrape_success{target="bmc-2"} 1
+# HELP idrac_gpu_power_watt Power of the GPU
+# TYPE idrac_gpu_power_watt gauge
+idrac_gpu_power_watt{id="GPU1",target="bmc-1"} 310
+idrac_gpu_power_watt{id="GPU1",target="bmc-2"} 420
+`,
+		},
+		{
+			name:    "failed target",
+			targets: []string{"bmc-1", "bmc-2"},
+			families: map[string][]*dto.MetricFamily{
+				"bmc-1": powerFamily(310, "id", "GPU1"),
+			},
+			want: `# HELP idrac_gpu_exporter_target_scrape_success Whether the collection of metrics from the target succeeded
+# TYPE idrac_gpu_exporter_target_scrape_success gauge
+idrac_gpu_exporter_target_scrape_success{target="bmc-1"} 1
+idrac_gpu_exporter_target_scrape_success{target="bmc-2"} 0
+# HELP idrac_gpu_power_watt Power of the GPU
+# TYPE idrac_gpu_power_watt gauge
+idrac_gpu_power_watt{id="GPU1",target="bmc-1"} 310
+`,
+		},
+		{
+			name:    "target label clash",
+			targets: []string{"bmc-1"},
+			families: map[string][]*dto.MetricFamily{
+				"bmc-1": powerFamily(310, "id", "GPU1", "target", "rack-1"),
+			},
+			want: `# HELP idrac_gpu_exporter_target_scrape_success Whether the collection of metrics from the target succeeded
+# TYPE idrac_gpu_exporter_target_scrape_success gauge
+idrac_gpu_exporter_target_scrape_success{target="bmc-1"} 1
+# HELP idrac_gpu_power_watt Power of the GPU
+# TYPE idrac_gpu_power_watt gauge
+idrac_gpu_power_watt{id="GPU1",target="bmc-1"} 310
+`,
+		},
+	}
+
+	config.Config = config.NewConfig()
+	config.Config.MetricsPrefix = "idrac"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gatherTargets(tt.targets, 2, func(target string) ([]*dto.MetricFamily, error) {
+				families, ok := tt.families[target]
+				if !ok {
+					return nil, fmt.Errorf("host %s not reachable", target)
+				}
+				return families, nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("metrics =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGatherTargetsConcurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		concurrency int
+		want        int
+	}{
+		{"limited", 3, 3},
+		{"unlimited", 10, 8},
+		{"invalid", 0, 1},
+	}
+
+	config.Config = config.NewConfig()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			running, max, calls := 0, 0, 0
+
+			targets := []string{}
+			for i := 1; i <= 8; i++ {
+				targets = append(targets, fmt.Sprintf("bmc-%d", i))
+			}
+
+			_, err := gatherTargets(targets, tt.concurrency, func(target string) ([]*dto.MetricFamily, error) {
+				mu.Lock()
+				running++
+				calls++
+				if running > max {
+					max = running
+				}
+				mu.Unlock()
+
+				time.Sleep(20 * time.Millisecond)
+
+				mu.Lock()
+				running--
+				mu.Unlock()
+				return powerFamily(1, "id", "GPU1"), nil
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if calls != len(targets) {
+				t.Errorf("gathered %d targets, want %d", calls, len(targets))
+			}
+			if max > tt.want || max < 1 {
+				t.Errorf("%d targets gathered at once, want at most %d", max, tt.want)
+			}
+		})
+	}
+}
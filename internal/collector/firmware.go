@@ -0,0 +1,117 @@
+package collector
+
+import (
+	"strings"
+	"time"
+)
+
+// The firmware inventory rarely changes and reading it takes one request per
+// component, so it is refreshed at most this often.
+const firmwareRefreshInterval = time.Hour
+
+// Dell keeps the firmware images available for rollback in the inventory,
+// these are not running on the device.
+const firmwarePreviousPrefix = "Previous-"
+
+// refreshFirmware reads the firmware inventory of the update service, unless
+// it has been read recently or is being read. The first scrape reads it, like
+// the sensors of the GPUs, later scrapes refresh it in the background and use
+// the inventory read before, so they do not wait for it. The inventory is read
+// again by the next scrape when reading it fails.
+func (client *Client) refreshFirmware() {
+	client.firmwareMu.Lock()
+	if client.updatePath == "" || client.firmwareBusy || time.Since(client.firmwareTime) < firmwareRefreshInterval {
+		client.firmwareMu.Unlock()
+		return
+	}
+	client.firmwareBusy = true
+	first := !client.firmwareTried
+	client.firmwareTried = true
+	client.firmwareMu.Unlock()
+
+	if first {
+		client.readFirmware(client.redfish)
+		return
+	}
+
+	// The session of the client is refreshed by the scrapes
+	go client.readFirmware(client.redfish.withoutSession())
+}
+
+// readFirmware reads the firmware inventory of the update service and keeps
+// it, when it could be read.
+func (client *Client) readFirmware(redfish *Redfish) bool {
+	firmware, ok := readFirmwareInventory(redfish, client.updatePath)
+
+	client.firmwareMu.Lock()
+	defer client.firmwareMu.Unlock()
+
+	client.firmwareBusy = false
+	if ok {
+		client.firmware = firmware
+		client.firmwareTime = time.Now()
+	}
+
+	return ok
+}
+
+func readFirmwareInventory(redfish *Redfish, path string) ([]SoftwareInventory, bool) {
+	service := UpdateServiceResponse{}
+	if ok := redfish.Get(path, &service); !ok {
+		return nil, false
+	}
+
+	group := GroupResponse{}
+	if ok := redfish.Get(service.FirmwareInventory.OdataId, &group); !ok {
+		return nil, false
+	}
+
+	firmware := []SoftwareInventory{}
+	for _, c := range group.Members.GetLinks() {
+		entry := SoftwareInventory{}
+		if ok := redfish.Get(c, &entry); !ok {
+			continue
+		}
+
+		if strings.HasPrefix(entry.Id, firmwarePreviousPrefix) || entry.Version == "" {
+			continue
+		}
+
+		firmware = append(firmware, entry)
+	}
+
+	return firmware, true
+}
+
+// gpuFirmware returns the firmware inventory entries belonging to the GPU at
+// the given path. An entry belongs to the GPU when it lists the GPU as related
+// item, or when its id ends with the id of the GPU as done by Dell.
+func (client *Client) gpuFirmware(path, id string) []SoftwareInventory {
+	client.firmwareMu.Lock()
+	defer client.firmwareMu.Unlock()
+
+	seen := map[[2]string]bool{}
+	firmware := []SoftwareInventory{}
+
+	for _, entry := range client.firmware {
+		related := strings.HasSuffix(entry.Id, "__"+id)
+		for _, item := range entry.RelatedItem {
+			if item.OdataId == path {
+				related = true
+			}
+		}
+		if !related {
+			continue
+		}
+
+		key := [2]string{entry.Name, entry.Version}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		firmware = append(firmware, entry)
+	}
+
+	return firmware
+}
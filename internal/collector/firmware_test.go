@@ -0,0 +1,100 @@
+package collector
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadFirmware(t *testing.T) {
+	available := false
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case !available:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/redfish/v1/UpdateService":
+			_, _ = w.Write([]byte(`{"FirmwareInventory":{"@odata.id":"/redfish/v1/UpdateService/FirmwareInventory"}}`))
+		case r.URL.Path == "/redfish/v1/UpdateService/FirmwareInventory":
+			_, _ = w.Write([]byte(`{"Members":[
+				{"@odata.id":"/redfish/v1/UpdateService/FirmwareInventory/Current-1__Video.Slot.21-1"},
+				{"@odata.id":"/redfish/v1/UpdateService/FirmwareInventory/Previous-1__Video.Slot.21-1"}
+			]}`))
+		case r.URL.Path == "/redfish/v1/UpdateService/FirmwareInventory/Current-1__Video.Slot.21-1":
+			_, _ = w.Write([]byte(`{"Id":"Current-1__Video.Slot.21-1","Name":"VBIOS","Version":"96.00.AF.00.01"}`))
+		case r.URL.Path == "/redfish/v1/UpdateService/FirmwareInventory/Previous-1__Video.Slot.21-1":
+			_, _ = w.Write([]byte(`{"Id":"Previous-1__Video.Slot.21-1","Name":"VBIOS","Version":"96.00.74.00.0B"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	client.updatePath = "/redfish/v1/UpdateService"
+
+	// A failed read is retried by the next scrape
+	if client.readFirmware(client.redfish) || !client.firmwareTime.IsZero() {
+		t.Fatalf("failed read was kept until %v", client.firmwareTime)
+	}
+
+	available = true
+	if !client.readFirmware(client.redfish) || client.firmwareTime.IsZero() {
+		t.Fatal("firmware inventory was not read")
+	}
+
+	firmware := client.gpuFirmware("/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.21-1", "Video.Slot.21-1")
+	if len(firmware) != 1 || firmware[0].Version != "96.00.AF.00.01" {
+		t.Errorf("unexpected firmware %+v", firmware)
+	}
+}
+
+// The first scrape reads the firmware inventory, later scrapes refresh it in
+// the background once the refresh interval has passed.
+func TestRefreshFirmware(t *testing.T) {
+	var mu sync.Mutex
+	version := "96.00.AF.00.01"
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.URL.Path {
+		case "/redfish/v1/UpdateService":
+			_, _ = w.Write([]byte(`{"FirmwareInventory":{"@odata.id":"/redfish/v1/UpdateService/FirmwareInventory"}}`))
+		case "/redfish/v1/UpdateService/FirmwareInventory":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/UpdateService/FirmwareInventory/Current-1__Video.Slot.21-1"}]}`))
+		case "/redfish/v1/UpdateService/FirmwareInventory/Current-1__Video.Slot.21-1":
+			_, _ = w.Write([]byte(`{"Id":"Current-1__Video.Slot.21-1","Name":"VBIOS","Version":"` + version + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	client.updatePath = "/redfish/v1/UpdateService"
+
+	const gpu = "/redfish/v1/Systems/System.Embedded.1/Processors/Video.Slot.21-1"
+	client.refreshFirmware()
+	if firmware := client.gpuFirmware(gpu, "Video.Slot.21-1"); len(firmware) != 1 || firmware[0].Version != "96.00.AF.00.01" {
+		t.Fatalf("first scrape read firmware %+v", firmware)
+	}
+
+	mu.Lock()
+	version = "96.00.B0.00.01"
+	mu.Unlock()
+
+	// Within the refresh interval the inventory is kept
+	client.refreshFirmware()
+	if firmware := client.gpuFirmware(gpu, "Video.Slot.21-1"); firmware[0].Version != "96.00.AF.00.01" {
+		t.Fatalf("inventory refreshed within the interval: %+v", firmware)
+	}
+
+	client.firmwareMu.Lock()
+	client.firmwareTime = time.Now().Add(-2 * firmwareRefreshInterval)
+	client.firmwareMu.Unlock()
+
+	client.refreshFirmware()
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if firmware := client.gpuFirmware(gpu, "Video.Slot.21-1"); firmware[0].Version == "96.00.B0.00.01" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("inventory not refreshed in the background")
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package collector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+)
+
+// newTestClient returns a client for a mock Redfish server serving the given
+// handler, along with a collector holding only the metric descriptors.
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *Collector) {
+	t.Helper()
+
+	config.Config = config.NewConfig()
+	config.Config.Timeout = 5
+	config.Config.Telemetry.Reports = []string{"GPUMetrics"}
+
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	client := &Client{
+		redfish: NewRedfish("https", strings.TrimPrefix(server.URL, "https://"), "user", "password"),
+	}
+
+	return client, newDescriptors("idrac", nil)
+}
+
+// drain collects the metrics sent to the returned channel until it is closed
+func drain() (chan prometheus.Metric, func() []prometheus.Metric) {
+	ch := make(chan prometheus.Metric)
+	done := make(chan []prometheus.Metric)
+	go func() {
+		metrics := []prometheus.Metric{}
+		for m := range ch {
+			metrics = append(metrics, m)
+		}
+		done <- metrics
+	}()
+
+	return ch, func() []prometheus.Metric {
+		close(ch)
+		return <-done
+	}
+}
+
+// readMetric returns the labels and the value of a counter or gauge
+func readMetric(t *testing.T, m prometheus.Metric) (map[string]string, float64, *dto.Metric) {
+	t.Helper()
+
+	pb := &dto.Metric{}
+	if err := m.Write(pb); err != nil {
+		t.Fatalf("failed to write metric: %v", err)
+	}
+
+	labels := map[string]string{}
+	for _, l := range pb.GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+
+	switch {
+	case pb.Counter != nil:
+		return labels, pb.GetCounter().GetValue(), pb
+	case pb.Gauge != nil:
+		return labels, pb.GetGauge().GetValue(), pb
+	}
+	return labels, 0, pb
+}
@@ -0,0 +1,159 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+// Maximum number of changes kept in the inventory history of a target
+const maxInventoryHistory = 100
+
+// inventory keeps track of the GPUs installed in each slot of a target and
+// records a change whenever a different GPU shows up in a slot.
+type inventory struct {
+	mu    sync.Mutex
+	path  string
+	state InventoryState
+}
+
+type InventoryItem struct {
+	SerialNumber string `json:"serial_number"`
+	UUID         string `json:"uuid"`
+	PartNumber   string `json:"part_number"`
+}
+
+type InventorySlot struct {
+	InventoryItem
+	Changes    uint64    `json:"changes"`
+	LastChange time.Time `json:"last_change"`
+}
+
+type InventoryChange struct {
+	Id        string        `json:"id"`
+	Timestamp time.Time     `json:"timestamp"`
+	Old       InventoryItem `json:"old"`
+	New       InventoryItem `json:"new"`
+}
+
+type InventoryState struct {
+	Slots   map[string]*InventorySlot `json:"slots"`
+	History []InventoryChange         `json:"history"`
+}
+
+func newInventory(target string) *inventory {
+	inv := &inventory{
+		path: stateFile(target, "inventory"),
+	}
+
+	loadState(inv.path, &inv.state)
+	if inv.state.Slots == nil {
+		inv.state.Slots = map[string]*InventorySlot{}
+	}
+
+	return inv
+}
+
+// differs reports whether the identifiers of two items differ, where empty
+// identifiers are considered unknown.
+func (a InventoryItem) differs(b InventoryItem) bool {
+	differ := func(x, y string) bool {
+		return x != "" && y != "" && x != y
+	}
+	return differ(a.SerialNumber, b.SerialNumber) || differ(a.UUID, b.UUID) || differ(a.PartNumber, b.PartNumber)
+}
+
+// merge returns the item with the unknown identifiers filled in from b.
+func (a InventoryItem) merge(b InventoryItem) InventoryItem {
+	if a.SerialNumber == "" {
+		a.SerialNumber = b.SerialNumber
+	}
+	if a.UUID == "" {
+		a.UUID = b.UUID
+	}
+	if a.PartNumber == "" {
+		a.PartNumber = b.PartNumber
+	}
+	return a
+}
+
+// Update records the GPU currently found in the slot and returns the state of
+// the slot. The first time a slot is seen counts as its last change.
+func (inv *inventory) Update(info *GPUInfo, now time.Time) InventorySlot {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	item := InventoryItem{
+		SerialNumber: info.SerialNumber,
+		UUID:         info.UUID,
+		PartNumber:   info.PartNumber,
+	}
+
+	slot, ok := inv.state.Slots[info.Id]
+	if !ok {
+		slot = &InventorySlot{
+			InventoryItem: item,
+			LastChange:    now,
+		}
+		inv.state.Slots[info.Id] = slot
+		return *slot
+	}
+
+	if slot.InventoryItem.differs(item) {
+		inv.state.History = append(inv.state.History, InventoryChange{
+			Id:        info.Id,
+			Timestamp: now,
+			Old:       slot.InventoryItem,
+			New:       item,
+		})
+		if len(inv.state.History) > maxInventoryHistory {
+			inv.state.History = inv.state.History[len(inv.state.History)-maxInventoryHistory:]
+		}
+		slot.InventoryItem = item
+		slot.Changes++
+		slot.LastChange = now
+	} else {
+		slot.InventoryItem = slot.InventoryItem.merge(item)
+	}
+
+	return *slot
+}
+
+// State returns a copy of the inventory state.
+func (inv *inventory) State() InventoryState {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	state := InventoryState{
+		Slots:   make(map[string]*InventorySlot, len(inv.state.Slots)),
+		History: append([]InventoryChange{}, inv.state.History...),
+	}
+	for k, v := range inv.state.Slots {
+		slot := *v
+		state.Slots[k] = &slot
+	}
+
+	return state
+}
+
+// Save writes the inventory to the state file, if persistence is enabled.
+func (inv *inventory) Save() {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+
+	saveState(inv.path, &inv.state)
+}
+
+// GetInventory returns the GPU inventory and change history of the target.
+// When the target has not been collected since the exporter started, the
+// inventory is read from the state file.
+func GetInventory(target string) InventoryState {
+	mu.Lock()
+	collector, ok := collectors[target]
+	mu.Unlock()
+
+	if ok {
+		return collector.inventory.State()
+	}
+
+	return newInventory(target).State()
+}
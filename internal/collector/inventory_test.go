@@ -0,0 +1,131 @@
+package collector
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+)
+
+func TestInventoryUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		items   []InventoryItem
+		changes uint64
+		want    InventoryItem
+	}{
+		{"same GPU", []InventoryItem{
+			{"S1", "U1", "P1"},
+			{"S1", "U1", "P1"},
+		}, 0, InventoryItem{"S1", "U1", "P1"}},
+		{"serial number", []InventoryItem{
+			{"S1", "U1", "P1"},
+			{"S2", "U2", "P1"},
+		}, 1, InventoryItem{"S2", "U2", "P1"}},
+		{"part number only", []InventoryItem{
+			{"", "", "P1"},
+			{"", "", "P2"},
+		}, 1, InventoryItem{"", "", "P2"}},
+		{"unknown identifiers", []InventoryItem{
+			{"S1", "", "P1"},
+			{"", "U1", ""},
+			{"S1", "", ""},
+		}, 0, InventoryItem{"S1", "U1", "P1"}},
+		{"swapped back", []InventoryItem{
+			{"S1", "U1", "P1"},
+			{"S2", "U2", "P1"},
+			{"S1", "U1", "P1"},
+		}, 2, InventoryItem{"S1", "U1", "P1"}},
+	}
+
+	config.Config = config.NewConfig()
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inv := newInventory("bmc-1")
+
+			var slot InventorySlot
+			lastChange := start
+			for i, item := range tt.items {
+				now := start.Add(time.Duration(i) * time.Minute)
+				prev := slot.Changes
+				slot = inv.Update(&GPUInfo{
+					Id:           "GPU1",
+					SerialNumber: item.SerialNumber,
+					UUID:         item.UUID,
+					PartNumber:   item.PartNumber,
+				}, now)
+				if slot.Changes != prev {
+					lastChange = now
+				}
+			}
+
+			if slot.Changes != tt.changes {
+				t.Errorf("changes = %d, want %d", slot.Changes, tt.changes)
+			}
+			if slot.InventoryItem != tt.want {
+				t.Errorf("item = %+v, want %+v", slot.InventoryItem, tt.want)
+			}
+			if !slot.LastChange.Equal(lastChange) {
+				t.Errorf("last change = %v, want %v", slot.LastChange, lastChange)
+			}
+
+			history := inv.State().History
+			if uint64(len(history)) != tt.changes {
+				t.Fatalf("history has %d changes, want %d", len(history), tt.changes)
+			}
+			if len(history) > 0 && history[len(history)-1].New != tt.items[len(tt.items)-1] {
+				t.Errorf("last change = %+v, want %+v", history[len(history)-1].New, tt.items[len(tt.items)-1])
+			}
+		})
+	}
+}
+
+func TestInventoryHistory(t *testing.T) {
+	config.Config = config.NewConfig()
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	inv := newInventory("bmc-1")
+	for i := 0; i <= maxInventoryHistory+20; i++ {
+		inv.Update(&GPUInfo{Id: "GPU1", SerialNumber: fmt.Sprintf("S%d", i)}, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	state := inv.State()
+	if n := len(state.History); n != maxInventoryHistory {
+		t.Fatalf("history has %d changes, want %d", n, maxInventoryHistory)
+	}
+	if first := state.History[0]; first.Old.SerialNumber != "S20" || first.New.SerialNumber != "S21" {
+		t.Errorf("oldest change = %+v, want S20 to S21", first)
+	}
+	if last := state.History[maxInventoryHistory-1]; last.New.SerialNumber != fmt.Sprintf("S%d", maxInventoryHistory+20) {
+		t.Errorf("newest change = %+v", last)
+	}
+	if slot := state.Slots["GPU1"]; slot.Changes != maxInventoryHistory+20 {
+		t.Errorf("changes = %d, want %d", slot.Changes, maxInventoryHistory+20)
+	}
+}
+
+func TestInventoryState(t *testing.T) {
+	config.Config = config.NewConfig()
+	config.Config.StateDir = t.TempDir()
+	start := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	inv := newInventory("bmc-1")
+	inv.Update(&GPUInfo{Id: "GPU1", SerialNumber: "S1"}, start)
+	inv.Update(&GPUInfo{Id: "GPU1", SerialNumber: "S2"}, start.Add(time.Minute))
+	inv.Save()
+
+	state := GetInventory("bmc-1")
+	if len(state.History) != 1 || state.Slots["GPU1"].SerialNumber != "S2" {
+		t.Fatalf("unexpected inventory %+v", state)
+	}
+
+	// A GPU replaced while the exporter was down is a change
+	inv = newInventory("bmc-1")
+	slot := inv.Update(&GPUInfo{Id: "GPU1", SerialNumber: "S3"}, start.Add(time.Hour))
+	if slot.Changes != 2 || !slot.LastChange.Equal(start.Add(time.Hour)) {
+		t.Errorf("unexpected slot %+v", slot)
+	}
+}
@@ -0,0 +1,409 @@
+package collector
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smc-public/idrac_gpu_exporter/internal/log"
+)
+
+// maxLogPages is the number of pages of a log read per scrape, which limits
+// the entries read when a log is tailed for the first time
+const maxLogPages = 10
+
+// logMessagePrefixes are the message ids of the log entries about GPUs and
+// PCIe devices, without the prefix of the message registry
+var logMessagePrefixes = []string{"GPU", "PCI"}
+
+// logTailer counts the GPU and PCIe related entries of the logs of a single
+// target. Only the entries added since the last scrape are read, so the
+// position in each log is kept along with the counts.
+type logTailer struct {
+	mu    sync.Mutex
+	path  string
+	state logState
+}
+
+type logState struct {
+	Logs   map[string]*logPosition `json:"logs"`
+	Counts []*logCount             `json:"counts"`
+}
+
+// logPosition is the last seen entry of a log and, for logs listed oldest
+// first, the number of entries before the next one to read, when the BMC
+// reports the number of entries of the log.
+type logPosition struct {
+	Last   string `json:"last"`
+	Offset int    `json:"offset,omitempty"`
+}
+
+type logCount struct {
+	Id        string  `json:"id"`
+	Severity  string  `json:"severity"`
+	MessageId string  `json:"message_id"`
+	Count     float64 `json:"count"`
+}
+
+func newLogTailer(target string) *logTailer {
+	t := &logTailer{
+		path: stateFile(target, "logs"),
+	}
+
+	loadState(t.path, &t.state)
+	if t.state.Logs == nil {
+		t.state.Logs = map[string]*logPosition{}
+	}
+
+	return t
+}
+
+// Position returns the position in the log, or nil when the log has not been
+// read before.
+func (t *logTailer) Position(path string) *logPosition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if pos, ok := t.state.Logs[path]; ok && pos != nil {
+		return &logPosition{Last: pos.Last, Offset: pos.Offset}
+	}
+	return nil
+}
+
+// Update counts the entries and records the position in the log.
+func (t *logTailer) Update(path string, pos *logPosition, entries []LogEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.state.Logs[path] = pos
+	for i := range entries {
+		id, messageId, ok := logEntryGPU(&entries[i])
+		if !ok {
+			continue
+		}
+		t.add(id, entries[i].Severity, messageId)
+	}
+}
+
+func (t *logTailer) add(id, severity, messageId string) {
+	for _, c := range t.state.Counts {
+		if c.Id == id && c.Severity == severity && c.MessageId == messageId {
+			c.Count++
+			return
+		}
+	}
+
+	t.state.Counts = append(t.state.Counts, &logCount{
+		Id:        id,
+		Severity:  severity,
+		MessageId: messageId,
+		Count:     1,
+	})
+}
+
+// Collect emits the log event counters.
+func (t *logTailer) Collect(mc *Collector, ch chan<- prometheus.Metric) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, c := range t.state.Counts {
+		ch <- prometheus.MustNewConstMetric(mc.GPULogEventsTotal, prometheus.CounterValue, c.Count, c.Id, c.Severity, c.MessageId)
+	}
+}
+
+// Save writes the log positions and counts to the state file, if persistence
+// is enabled.
+func (t *logTailer) Save() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	saveState(t.path, &t.state)
+}
+
+// RefreshLogs reads the entries added to the logs of the managers since the
+// last scrape and emits the counters of the GPU and PCIe related entries.
+func (client *Client) RefreshLogs(mc *Collector, ch chan<- prometheus.Metric) bool {
+	if client.logServices == nil {
+		client.findLogServices()
+	}
+
+	ok := true
+	for _, path := range client.logServices {
+		entries, pos, read := client.readLogEntries(path, mc.logs.Position(path))
+		if !read {
+			ok = false
+			continue
+		}
+		mc.logs.Update(path, pos, entries)
+	}
+
+	mc.logs.Collect(mc, ch)
+
+	return ok
+}
+
+// findLogServices looks up the entries of the logs of the managers. On Dell
+// servers the Lifecycle log also holds the events of the SEL, so only the
+// Lifecycle log is read when there is one, otherwise the logs of the SEL type.
+func (client *Client) findLogServices() {
+	client.logServices = []string{}
+
+	group := GroupResponse{}
+	if client.managersPath == "" || !client.redfish.Get(client.managersPath, &group) {
+		log.Info("No log services found for %s", client.redfish.hostname)
+		return
+	}
+
+	for _, c := range group.Members.GetLinks() {
+		manager := Manager{}
+		if ok := client.redfish.Get(c, &manager); !ok || manager.LogServices.OdataId == "" {
+			continue
+		}
+
+		services := GroupResponse{}
+		if ok := client.redfish.Get(manager.LogServices.OdataId, &services); !ok {
+			continue
+		}
+
+		lclog := ""
+		sel := []string{}
+		for _, s := range services.Members.GetLinks() {
+			service := LogService{}
+			if ok := client.redfish.Get(s, &service); !ok || service.Entries.OdataId == "" {
+				continue
+			}
+			if strings.EqualFold(service.Id, "Lclog") {
+				lclog = service.Entries.OdataId
+			} else if strings.EqualFold(service.LogEntryType, "SEL") {
+				sel = append(sel, service.Entries.OdataId)
+			}
+		}
+
+		if lclog != "" {
+			client.logServices = append(client.logServices, lclog)
+		} else {
+			client.logServices = append(client.logServices, sel...)
+		}
+	}
+}
+
+// readLogEntries returns the entries of the log added after the position,
+// along with the new position, where the last seen entry is the entry with
+// the highest id or the latest creation time. The first time a log is read,
+// only its newest entry is looked up, so the entries logged before are not
+// counted. Logs listed newest first are read until the last seen entry, logs
+// listed oldest first from the offset, when known, up to the page limit.
+// When the log has been cleared, all entries are read again. When a page can
+// not be read, no entries are returned and the position is kept, so the
+// entries are read again by the next scrape.
+func (client *Client) readLogEntries(path string, pos *logPosition) ([]LogEntry, *logPosition, bool) {
+	if pos == nil {
+		pos, ok := client.seekLogEnd(path)
+		return []LogEntry{}, pos, ok
+	}
+
+	last := pos.Last
+	all := []LogEntry{}
+	var count *int
+
+	next := logPage(path, pos.Offset)
+	for page := 0; next != "" && page < maxLogPages; page++ {
+		group := LogEntryCollection{}
+		if ok := client.redfish.Get(next, &group); !ok {
+			return nil, pos, false
+		}
+		if page == 0 {
+			count = group.Count
+		}
+		all = append(all, group.Members...)
+
+		newestFirst := pos.Offset == 0 && logNewestFirst(all)
+		if newestFirst && logEntryBefore(newestLogEntry(all), last) {
+			last = ""
+		}
+		if n := len(group.Members); newestFirst && n > 0 && logEntrySeen(&group.Members[n-1], last) {
+			break
+		}
+
+		next = group.NextLink
+	}
+
+	// The log has been cleared when it holds fewer entries than the offset,
+	// or only entries older than the last seen entry after it
+	if pos.Offset > 0 && (count == nil || *count < pos.Offset || logEntryBefore(newestLogEntry(all), pos.Last)) {
+		return client.readLogEntries(path, &logPosition{Last: pos.Last})
+	}
+
+	newest := newestLogEntry(all)
+	if newest == "" {
+		return []LogEntry{}, pos, true
+	}
+	if logEntryBefore(newest, last) {
+		last = ""
+	}
+
+	// The last seen entry is compared by position when the ids are not numeric
+	seen := -1
+	for i := range all {
+		if all[i].Id == last {
+			seen = i
+			break
+		}
+	}
+
+	entries := []LogEntry{}
+	for i := range all {
+		e := &all[i]
+		switch {
+		case logEntrySeen(e, last):
+		case seen >= 0 && logNewestFirst(all) && i > seen:
+		case seen >= 0 && !logNewestFirst(all) && i < seen:
+		default:
+			entries = append(entries, *e)
+		}
+	}
+
+	offset := 0
+	if count != nil && (pos.Offset > 0 || !logNewestFirst(all)) {
+		offset = pos.Offset + len(all)
+	}
+
+	return entries, &logPosition{Last: newest, Offset: offset}, true
+}
+
+// seekLogEnd returns the position of the newest entry of the log. The newest
+// entry of a log listed oldest first is read from the last page, when the BMC
+// reports the number of entries, and else by reading all pages once.
+func (client *Client) seekLogEnd(path string) (*logPosition, bool) {
+	group := LogEntryCollection{}
+	if ok := client.redfish.Get(path, &group); !ok {
+		return nil, false
+	}
+
+	all := group.Members
+	if logNewestFirst(all) {
+		return &logPosition{Last: newestLogEntry(all)}, true
+	}
+
+	if group.Count != nil && *group.Count > len(all) {
+		tail := LogEntryCollection{}
+		if ok := client.redfish.Get(logPage(path, *group.Count-1), &tail); !ok {
+			return nil, false
+		}
+		all = append(all, tail.Members...)
+	} else if group.Count == nil {
+		for next := group.NextLink; next != ""; {
+			page := LogEntryCollection{}
+			if ok := client.redfish.Get(next, &page); !ok {
+				return nil, false
+			}
+			all = append(all, page.Members...)
+			next = page.NextLink
+		}
+	}
+
+	pos := &logPosition{Last: newestLogEntry(all)}
+	if group.Count != nil {
+		pos.Offset = *group.Count
+	}
+	return pos, true
+}
+
+// logPage returns the path of the entries of the log, starting after the
+// given number of entries.
+func logPage(path string, skip int) string {
+	if skip <= 0 {
+		return path
+	}
+	return path + "?$skip=" + strconv.Itoa(skip)
+}
+
+// logNewestFirst reports whether the entries are listed newest first, which
+// is assumed when it can not be told.
+func logNewestFirst(entries []LogEntry) bool {
+	if len(entries) < 2 {
+		return true
+	}
+	return !logEntryAfter(&entries[len(entries)-1], &entries[0])
+}
+
+// newestLogEntry returns the id of the entry with the highest id or the
+// latest creation time.
+func newestLogEntry(entries []LogEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+
+	newest := &entries[0]
+	for i := range entries {
+		if logEntryAfter(&entries[i], newest) {
+			newest = &entries[i]
+		}
+	}
+	return newest.Id
+}
+
+// logEntryAfter reports whether the entry was added after the other entry,
+// by their numeric ids or else by their creation times.
+func logEntryAfter(e, other *LogEntry) bool {
+	if _, err := strconv.ParseUint(e.Id, 10, 64); err == nil {
+		if _, err := strconv.ParseUint(other.Id, 10, 64); err == nil {
+			return logEntryBefore(other.Id, e.Id)
+		}
+	}
+
+	a, err := time.Parse(time.RFC3339, e.Created)
+	if err != nil {
+		return false
+	}
+	b, err := time.Parse(time.RFC3339, other.Created)
+	if err != nil {
+		return false
+	}
+	return a.After(b)
+}
+
+// logEntrySeen reports whether the entry is the last seen entry or was added
+// before it, which can only be told for numeric ids.
+func logEntrySeen(e *LogEntry, last string) bool {
+	return last != "" && (e.Id == last || logEntryBefore(e.Id, last))
+}
+
+// logEntryBefore reports whether the entry was added before the last seen
+// entry, which can only be told for numeric ids.
+func logEntryBefore(id, last string) bool {
+	a, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return false
+	}
+	b, err := strconv.ParseUint(last, 10, 64)
+	if err != nil {
+		return false
+	}
+	return a < b
+}
+
+// logEntryGPU returns the GPU and the message id of a log entry, when the
+// entry relates to a GPU or a PCIe device. The id is empty for PCIe entries
+// that can not be matched to a GPU.
+func logEntryGPU(e *LogEntry) (string, string, bool) {
+	messageId := e.MessageId
+	if i := strings.LastIndex(messageId, "."); i >= 0 {
+		messageId = messageId[i+1:]
+	}
+
+	id := gpuReference(e.Links.OriginOfCondition.OdataId, e.MessageArgs)
+	if id != "" {
+		return id, messageId, true
+	}
+
+	for _, prefix := range logMessagePrefixes {
+		if strings.HasPrefix(messageId, prefix) {
+			return "", messageId, true
+		}
+	}
+
+	return "", "", false
+}
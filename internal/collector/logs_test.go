@@ -0,0 +1,247 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+const logPath = "/redfish/v1/Managers/1/LogServices/Sel/Entries"
+
+// fakeLog serves the entries of a log in pages of two entries, starting at
+// the entry given by $skip
+type fakeLog struct {
+	mu          sync.Mutex
+	ids         []string
+	newestFirst bool
+	count       bool
+	failPage    int
+	skips       []int
+}
+
+func (f *fakeLog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	skip, _ := strconv.Atoi(r.URL.Query().Get("$skip"))
+	f.skips = append(f.skips, skip)
+	if skip/2 == f.failPage {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	ids := append([]string{}, f.ids...)
+	if f.newestFirst {
+		for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+			ids[i], ids[j] = ids[j], ids[i]
+		}
+	}
+
+	group := LogEntryCollection{}
+	for i := skip; i < len(ids) && i < skip+2; i++ {
+		group.Members = append(group.Members, LogEntry{
+			Id:        ids[i],
+			MessageId: "PCI3008",
+			Severity:  "Critical",
+		})
+	}
+	if f.count {
+		n := len(ids)
+		group.Count = &n
+	}
+	if skip+2 < len(ids) {
+		group.NextLink = fmt.Sprintf(logPath+"?$skip=%d", skip+2)
+	}
+
+	_ = json.NewEncoder(w).Encode(&group)
+}
+
+func (f *fakeLog) add(ids ...string) {
+	f.mu.Lock()
+	f.ids = append(f.ids, ids...)
+	f.mu.Unlock()
+}
+
+// requests returns the entries skipped by the requests since the last call
+func (f *fakeLog) requests() []int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	skips := f.skips
+	f.skips = nil
+	return skips
+}
+
+func logIds(from, to int) []string {
+	ids := []string{}
+	for i := from; i <= to; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	return ids
+}
+
+func TestReadLogEntries(t *testing.T) {
+	tests := []struct {
+		name        string
+		newestFirst bool
+		count       bool
+	}{
+		{"newest first", true, false},
+		{"oldest first", false, false},
+		{"oldest first with count", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &fakeLog{ids: []string{"1", "2", "3"}, newestFirst: tt.newestFirst, count: tt.count, failPage: -1}
+			client, _ := newTestClient(t, log.ServeHTTP)
+
+			// The entries logged before the first scrape are not counted
+			entries, pos, ok := client.readLogEntries(logPath, nil)
+			if !ok || len(entries) != 0 || pos.Last != "3" {
+				t.Fatalf("first scrape: %d entries, position %+v, ok %v", len(entries), pos, ok)
+			}
+
+			log.add("4", "5", "6")
+			entries, pos, ok = client.readLogEntries(logPath, pos)
+			if !ok || len(entries) != 3 || pos.Last != "6" {
+				t.Fatalf("second scrape: %d entries, position %+v, ok %v", len(entries), pos, ok)
+			}
+			for _, e := range entries {
+				if logEntryBefore(e.Id, "4") {
+					t.Errorf("entry %s was read again", e.Id)
+				}
+			}
+
+			entries, pos, ok = client.readLogEntries(logPath, pos)
+			if !ok || len(entries) != 0 || pos.Last != "6" {
+				t.Fatalf("third scrape: %d entries, position %+v, ok %v", len(entries), pos, ok)
+			}
+		})
+	}
+}
+
+func TestReadLogEntriesEmpty(t *testing.T) {
+	log := &fakeLog{newestFirst: true, failPage: -1}
+	client, _ := newTestClient(t, log.ServeHTTP)
+
+	entries, pos, ok := client.readLogEntries(logPath, nil)
+	if !ok || len(entries) != 0 || pos == nil || pos.Last != "" {
+		t.Fatalf("first scrape: %d entries, position %+v, ok %v", len(entries), pos, ok)
+	}
+
+	// The entries logged after the first scrape of an empty log are counted
+	log.add("1", "2")
+	entries, pos, ok = client.readLogEntries(logPath, pos)
+	if !ok || len(entries) != 2 || pos.Last != "2" {
+		t.Fatalf("second scrape: %d entries, position %+v, ok %v", len(entries), pos, ok)
+	}
+}
+
+// A long log listed oldest first is read from the last seen entry, and not
+// from its first page, when the BMC reports the number of entries.
+func TestReadLogEntriesOldestFirst(t *testing.T) {
+	log := &fakeLog{ids: logIds(1, 30), count: true, failPage: -1}
+	client, _ := newTestClient(t, log.ServeHTTP)
+
+	entries, pos, ok := client.readLogEntries(logPath, nil)
+	if !ok || len(entries) != 0 || *pos != (logPosition{Last: "30", Offset: 30}) {
+		t.Fatalf("first scrape: %d entries, position %+v, ok %v", len(entries), pos, ok)
+	}
+	if skips := log.requests(); !reflect.DeepEqual(skips, []int{0, 29}) {
+		t.Errorf("first scrape skipped %v entries, want the first and the last page", skips)
+	}
+
+	// More pages than are read by a scrape
+	log.add(logIds(31, 55)...)
+	entries, pos, ok = client.readLogEntries(logPath, pos)
+	if !ok || len(entries) != 2*maxLogPages || *pos != (logPosition{Last: "50", Offset: 50}) {
+		t.Fatalf("second scrape: %d entries, position %+v, ok %v", len(entries), pos, ok)
+	}
+	if skips := log.requests(); len(skips) != maxLogPages || skips[0] != 30 {
+		t.Errorf("second scrape skipped %v entries, want %d pages from 30", skips, maxLogPages)
+	}
+
+	entries, pos, ok = client.readLogEntries(logPath, pos)
+	if !ok || len(entries) != 5 || *pos != (logPosition{Last: "55", Offset: 55}) {
+		t.Fatalf("third scrape: %d entries, position %+v, ok %v", len(entries), pos, ok)
+	}
+	for _, e := range entries {
+		if logEntryBefore(e.Id, "51") {
+			t.Errorf("entry %s was read again", e.Id)
+		}
+	}
+}
+
+func TestReadLogEntriesFailure(t *testing.T) {
+	log := &fakeLog{ids: []string{"1", "2", "3", "4", "5"}, newestFirst: true, failPage: 1}
+	client, _ := newTestClient(t, log.ServeHTTP)
+
+	entries, pos, ok := client.readLogEntries(logPath, &logPosition{Last: "1"})
+	if ok || len(entries) != 0 || pos.Last != "1" {
+		t.Fatalf("partial read: %d entries, position %+v, ok %v", len(entries), pos, ok)
+	}
+
+	log.failPage = -1
+	entries, pos, ok = client.readLogEntries(logPath, pos)
+	if !ok || len(entries) != 4 || pos.Last != "5" {
+		t.Fatalf("next scrape: %d entries, position %+v, ok %v", len(entries), pos, ok)
+	}
+}
+
+func TestReadLogEntriesCleared(t *testing.T) {
+	tests := []struct {
+		name        string
+		newestFirst bool
+		pos         logPosition
+	}{
+		{"newest first", true, logPosition{Last: "40"}},
+		{"oldest first", false, logPosition{Last: "40", Offset: 40}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := &fakeLog{ids: []string{"1", "2"}, newestFirst: tt.newestFirst, count: true, failPage: -1}
+			client, _ := newTestClient(t, log.ServeHTTP)
+
+			entries, pos, ok := client.readLogEntries(logPath, &tt.pos)
+			if !ok || len(entries) != 2 || pos.Last != "2" {
+				t.Fatalf("cleared log: %d entries, position %+v, ok %v", len(entries), pos, ok)
+			}
+		})
+	}
+}
+
+func TestLogEntryGPU(t *testing.T) {
+	tests := []struct {
+		name      string
+		messageId string
+		origin    string
+		args      []string
+		id        string
+		want      string
+		ok        bool
+	}{
+		{"processor", "TMP0120", "/redfish/v1/Systems/1/Processors/GPU1", nil, "GPU1", "TMP0120", true},
+		{"fqdd", "IDRAC.2.9.TMP0118", "", []string{"Video.Slot.21-1"}, "Video.Slot.21-1", "TMP0118", true},
+		{"pcie", "Base.1.0.PCI1302", "", nil, "", "PCI1302", true},
+		{"cpu", "CPU0001", "/redfish/v1/Systems/1/Processors/CPU1", nil, "", "", false},
+		{"other", "PSU0003", "", nil, "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := LogEntry{MessageId: tt.messageId, MessageArgs: tt.args}
+			e.Links.OriginOfCondition.OdataId = tt.origin
+
+			id, messageId, ok := logEntryGPU(&e)
+			if id != tt.id || messageId != tt.want || ok != tt.ok {
+				t.Errorf("logEntryGPU = %q, %q, %v, want %q, %q, %v", id, messageId, ok, tt.id, tt.want, tt.ok)
+			}
+		})
+	}
+}
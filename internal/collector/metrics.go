@@ -1,11 +1,32 @@
 package collector
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+func bool2value(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// pcieType2value converts a Redfish PCIe type (e.g. Gen5) to its generation
+func pcieType2value(t string) (bool, int) {
+	s, ok := strings.CutPrefix(t, "Gen")
+	if !ok {
+		return false, 0
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return false, 0
+	}
+	return true, v
+}
+
 func gpuHealth2value(gpuHealth string) (bool, int) {
 	switch gpuHealth {
 	case "Critical":
@@ -21,6 +42,52 @@ func gpuHealth2value(gpuHealth string) (bool, int) {
 	}
 }
                 
+func health2value(health string) (bool, int) {
+	switch health {
+	case "Critical":
+		return true, 0
+	case "Warning":
+		return true, 1
+	case "OK":
+		return true, 2
+	default:
+		return false, 0
+	}
+}
+
+func state2value(state string) (bool, int) {
+	switch state {
+	case "Enabled":
+		return true, 0
+	case "Disabled":
+		return true, 1
+	case "StandbyOffline":
+		return true, 2
+	case "StandbySpare":
+		return true, 3
+	case "InTest":
+		return true, 4
+	case "Starting":
+		return true, 5
+	case "Absent":
+		return true, 6
+	case "UnavailableOffline":
+		return true, 7
+	case "Deferring":
+		return true, 8
+	case "Quiesced":
+		return true, 9
+	case "Updating":
+		return true, 10
+	case "Qualified":
+		return true, 11
+	case "Degraded":
+		return true, 12
+	default:
+		return false, 0
+	}
+}
+
 func gpuState2value(gpuState string) (bool, int) {
 	switch gpuState {
 	case "Available":
@@ -73,6 +140,60 @@ func thermalAlertStatus2value(thermalAlertStatus string) (bool, int) {
 	}
 }
 
+func linkStatus2value(linkStatus string) (bool, int) {
+	switch linkStatus {
+	case "LinkDown":
+		return true, 0
+	case "LinkUp":
+		return true, 1
+	case "NoLink":
+		return true, 2
+	case "Starting":
+		return true, 3
+	case "Training":
+		return true, 4
+	default:
+		return false, 0
+	}
+}
+
+func (mc *Collector) NewSystemInfo(ch chan<- prometheus.Metric, m *SystemResponse) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.SystemInfo,
+		prometheus.UntypedValue,
+		1.0,
+		strings.TrimSpace(m.Model),
+		strings.TrimSpace(m.SerialNumber),
+		strings.TrimSpace(m.SKU),
+		strings.TrimSpace(m.BiosVersion),
+		strings.TrimSpace(m.HostName),
+	)
+}
+
+func (mc *Collector) NewSystemPowerOn(ch chan<- prometheus.Metric, m *SystemResponse) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.SystemPowerOn,
+		prometheus.GaugeValue,
+		bool2value(m.PowerState == "On"),
+	)
+}
+
+func (mc *Collector) NewSystemHealth(ch chan<- prometheus.Metric, m *SystemResponse) {
+	health := m.Status.HealthRollup
+	if health == "" {
+		health = m.Status.Health
+	}
+
+	if ok, value := health2value(health); ok {
+		ch <- prometheus.MustNewConstMetric(
+			mc.SystemHealth,
+			prometheus.GaugeValue,
+			float64(value),
+			health,
+		)
+	}
+}
+
 func (mc *Collector) NewGPUInfo(ch chan<- prometheus.Metric, m *GPUInfo) {
 	ch <- prometheus.MustNewConstMetric(
 		mc.GPUInfo,
@@ -84,29 +205,136 @@ func (mc *Collector) NewGPUInfo(ch chan<- prometheus.Metric, m *GPUInfo) {
 		strings.TrimSpace(m.PartNumber),
 		strings.TrimSpace(m.SerialNumber),
 		strings.TrimSpace(m.UUID),
+		strings.TrimSpace(m.MemoryType),
+		m.AcceleratorType,
 	)
 }
 
-func (mc *Collector) NewGPUState(ch chan<- prometheus.Metric, m *DellVideoMember) {
-	if ok, value := gpuState2value(m.GPUState); ok {
+func (mc *Collector) NewGPUMemoryTotalBytes(ch chan<- prometheus.Metric, m *GPU) {
+	if m.MemorySummary.TotalMemorySizeMiB != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUMemoryTotalBytes,
+			prometheus.GaugeValue,
+			*m.MemorySummary.TotalMemorySizeMiB*1024*1024,
+			m.Id,
+		)
+	}
+}
+
+func (mc *Collector) NewGPUECCModeEnabled(ch chan<- prometheus.Metric, m *GPU) {
+	if m.MemorySummary.ECCModeEnabled != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUECCModeEnabled,
+			prometheus.GaugeValue,
+			bool2value(*m.MemorySummary.ECCModeEnabled),
+			m.Id,
+		)
+	}
+}
+
+func (mc *Collector) NewGPUInventory(ch chan<- prometheus.Metric, id string, m InventorySlot) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUInventoryChangesTotal,
+		prometheus.CounterValue,
+		float64(m.Changes),
+		id,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUInfoLastChangeTimestamp,
+		prometheus.GaugeValue,
+		float64(m.LastChange.Unix()),
+		id,
+	)
+}
+
+func (mc *Collector) NewGPUFirmwareInfo(ch chan<- prometheus.Metric, id, component, version string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUFirmwareInfo,
+		prometheus.UntypedValue,
+		1.0,
+		id,
+		strings.TrimSpace(component),
+		strings.TrimSpace(version),
+	)
+}
+
+func (mc *Collector) NewGPUState(ch chan<- prometheus.Metric, id, state string) {
+	if ok, value := gpuState2value(state); ok {
 		ch <- prometheus.MustNewConstMetric(
 			mc.GPUState,
 			prometheus.GaugeValue,
 			float64(value),
-			m.Id,
-			m.GPUState,
+			id,
+			state,
 		)
 	}
 }
 
-func (mc *Collector) NewGPUHealth(ch chan<- prometheus.Metric, m *DellVideoMember) {
-	if ok, value := gpuHealth2value(m.GPUHealth); ok {
+func (mc *Collector) NewGPUHealth(ch chan<- prometheus.Metric, id, health string) {
+	if ok, value := gpuHealth2value(health); ok {
 		ch <- prometheus.MustNewConstMetric(
 			mc.GPUHealth,
 			prometheus.GaugeValue,
 			float64(value),
-			m.Id,
-			m.GPUHealth,
+			id,
+			health,
+		)
+	}
+}
+
+func (mc *Collector) NewGPURedfishState(ch chan<- prometheus.Metric, id string, m *Status) {
+	if ok, value := state2value(m.State); ok {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPURedfishState,
+			prometheus.GaugeValue,
+			float64(value),
+			id,
+			m.State,
+		)
+	}
+}
+
+func (mc *Collector) NewGPURedfishHealth(ch chan<- prometheus.Metric, id string, m *Status) {
+	if ok, value := health2value(m.Health); ok {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPURedfishHealth,
+			prometheus.GaugeValue,
+			float64(value),
+			id,
+			m.Health,
+		)
+	}
+}
+
+func (mc *Collector) NewGPUSensorTemperatureCelsius(ch chan<- prometheus.Metric, v float64, id, sensor string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUSensorTemperatureCelsius,
+		prometheus.GaugeValue,
+		v,
+		id,
+		sensor,
+	)
+}
+
+func (mc *Collector) NewGPUSensorPowerWatt(ch chan<- prometheus.Metric, v float64, id, sensor string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUSensorPowerWatt,
+		prometheus.GaugeValue,
+		v,
+		id,
+		sensor,
+	)
+}
+
+func (mc *Collector) NewGPUSensorHealth(ch chan<- prometheus.Metric, id, sensor, health string) {
+	if ok, value := health2value(health); ok {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUSensorHealth,
+			prometheus.GaugeValue,
+			float64(value),
+			id,
+			sensor,
+			health,
 		)
 	}
 }
@@ -123,12 +351,12 @@ func (mc *Collector) NewBoardPowerSupplyStatus(ch chan<- prometheus.Metric, m *D
 	}
 }
 
-func (mc *Collector) NewMemoryTemperatureCelsius(ch chan<- prometheus.Metric, m *DellGPUSensorMember) {
+func (mc *Collector) NewMemoryTemperatureCelsius(ch chan<- prometheus.Metric, v float64, id string) {
 	ch <- prometheus.MustNewConstMetric(
 		mc.GPUMemoryTemperatureCelsius,
 		prometheus.GaugeValue,
-		m.MemoryTemperatureCelsius,
-		m.Id,
+		v,
+		id,
 	)
 }
 
@@ -144,12 +372,21 @@ func (mc *Collector) NewPowerBrakeStatus(ch chan<- prometheus.Metric, m *DellGPU
 	}
 }
 
-func (mc *Collector) NewPrimaryGPUTemperatureCelsius(ch chan<- prometheus.Metric, m *DellGPUSensorMember) {
+// NewPrimaryGPUTemperatureCelsius emits the primary temperature of the GPU,
+// also as vendor-neutral temperature along with the resource it is read from
+func (mc *Collector) NewPrimaryGPUTemperatureCelsius(ch chan<- prometheus.Metric, v float64, id, source string) {
 	ch <- prometheus.MustNewConstMetric(
 		mc.GPUPrimaryGPUTemperatureCelsius,
 		prometheus.GaugeValue,
-		m.PrimaryGPUTemperatureCelsius,
-		m.Id,
+		v,
+		id,
+	)
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUTemperatureCelsius,
+		prometheus.GaugeValue,
+		v,
+		id,
+		source,
 	)
 }
 
@@ -190,11 +427,11 @@ func (mc *Collector) NewGPUThrottleReasons(ch chan<- prometheus.Metric, v []stri
 	}
 }
 
-func (mc *Collector) NewGPUSMUtilizationPercent(ch chan<- prometheus.Metric, v int , id string) {
+func (mc *Collector) NewGPUSMUtilizationPercent(ch chan<- prometheus.Metric, v float64 , id string) {
 	ch <- prometheus.MustNewConstMetric(
 		mc.GPUSMUtilizationPercent,
 		prometheus.GaugeValue,
-		float64(v),
+		v,
 		id,
 	)
 }
@@ -280,15 +517,47 @@ func (mc *Collector) NewGPUDRAMUtilizationPercent(ch chan<- prometheus.Metric, v
 	)
 }
 
-func (mc *Collector) NewGPUPCIeCorrectableErrorCount(ch chan<- prometheus.Metric, v int , id string) {
+func (mc *Collector) NewGPUPCIeCorrectableErrorCount(ch chan<- prometheus.Metric, v float64 , id string) {
 	ch <- prometheus.MustNewConstMetric(
 		mc.GPUPCIeCorrectableErrorCount,
 		prometheus.CounterValue,
-		float64(v),
+		v,
 		id,
 	)
 }
 
+// NewGPUPCIeErrors emits the PCIe error counters of the GPU, corrected for
+// resets of the BMC.
+func (mc *Collector) NewGPUPCIeErrors(ch chan<- prometheus.Metric, id, serial string, m *PCIeErrors) {
+	v := mc.counters.Update(id, serial, "correctable", float64(m.CorrectableErrorCount))
+	mc.NewGPUPCIeCorrectableErrorCount(ch, v, id)
+
+	counters := []struct {
+		name  string
+		value *int
+	}{
+		{"fatal", m.FatalErrorCount},
+		{"non_fatal", m.NonFatalErrorCount},
+		{"l0_to_recovery", m.L0ToRecoveryCount},
+		{"replay", m.ReplayCount},
+		{"replay_rollover", m.ReplayRolloverCount},
+		{"nak_sent", m.NAKSentCount},
+		{"nak_received", m.NAKReceivedCount},
+	}
+	for _, c := range counters {
+		if c.value == nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUPCIeErrors,
+			prometheus.CounterValue,
+			mc.counters.Update(id, serial, c.name, float64(*c.value)),
+			id,
+			c.name,
+		)
+	}
+}
+
 func (mc *Collector) NewGPUBandwidthPercent(ch chan<- prometheus.Metric, m *GPUMetrics) {
 	if m.BandwidthPercent == nil {
 		return
@@ -319,11 +588,426 @@ func (mc *Collector) NewGPUMemoryBandwidthPercent(ch chan<- prometheus.Metric, i
 	)
 }
 
+func (mc *Collector) NewGPUMemoryECCErrors(ch chan<- prometheus.Metric, id, period string, m *MemoryErrorCounts) {
+	if m == nil {
+		return
+	}
+	if m.CorrectableECCErrorCount != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUMemoryCorrectableECCErrors,
+			prometheus.CounterValue,
+			float64(*m.CorrectableECCErrorCount),
+			id,
+			period,
+		)
+	}
+	if m.UncorrectableECCErrorCount != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUMemoryUncorrectableECCErrors,
+			prometheus.CounterValue,
+			float64(*m.UncorrectableECCErrorCount),
+			id,
+			period,
+		)
+	}
+}
+
+func (mc *Collector) NewGPUMemoryRowRemapping(ch chan<- prometheus.Metric, id string, m *NvidiaMemoryMetrics) {
+	if m.RowRemapping != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUMemoryCorrectableRowRemaps,
+			prometheus.CounterValue,
+			float64(m.RowRemapping.CorrectableRowRemappingCount),
+			id,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUMemoryUncorrectableRowRemaps,
+			prometheus.CounterValue,
+			float64(m.RowRemapping.UncorrectableRowRemappingCount),
+			id,
+		)
+	}
+	if m.RowRemappingPending != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUMemoryRowRemappingPending,
+			prometheus.GaugeValue,
+			bool2value(*m.RowRemappingPending),
+			id,
+		)
+	}
+	if m.RowRemappingFailed != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUMemoryRowRemappingFailed,
+			prometheus.GaugeValue,
+			bool2value(*m.RowRemappingFailed),
+			id,
+		)
+	}
+}
+
 func (mc *Collector) NewGPUConsumedPowerWatt(ch chan<- prometheus.Metric, m *GPUMetrics) {
+	if m.ConsumedPowerWatt == nil {
+		return
+	}
 	ch <- prometheus.MustNewConstMetric(
 		mc.GPUConsumedPowerWatt,
 		prometheus.GaugeValue,
-		m.ConsumedPowerWatt,
+		*m.ConsumedPowerWatt,
 		m.Id,
 	)
 }
+
+func (mc *Collector) NewGPUEnergyJoulesTotal(ch chan<- prometheus.Metric, v float64, id string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUEnergyJoulesTotal,
+		prometheus.CounterValue,
+		v,
+		id,
+	)
+}
+
+func (mc *Collector) NewGPUTDPWatt(ch chan<- prometheus.Metric, v float64, id string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUTDPWatt,
+		prometheus.GaugeValue,
+		v,
+		id,
+	)
+}
+
+func (mc *Collector) NewGPUPowerLimitWatt(ch chan<- prometheus.Metric, v float64, id string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUPowerLimitWatt,
+		prometheus.GaugeValue,
+		v,
+		id,
+	)
+}
+
+func (mc *Collector) NewGPUPowerHeadroomWatt(ch chan<- prometheus.Metric, v float64, id string) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUPowerHeadroomWatt,
+		prometheus.GaugeValue,
+		v,
+		id,
+	)
+}
+
+func (mc *Collector) NewGPUHostEnergyJoulesTotal(ch chan<- prometheus.Metric, v float64) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUHostEnergyJoulesTotal,
+		prometheus.CounterValue,
+		v,
+	)
+}
+
+func (mc *Collector) NewGPUNVLinkPort(ch chan<- prometheus.Metric, gpu string, m *Port) {
+	if ok, value := linkStatus2value(m.LinkStatus); ok {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUNVLinkStatus,
+			prometheus.GaugeValue,
+			float64(value),
+			gpu,
+			m.Id,
+			m.LinkStatus,
+		)
+	}
+	if m.CurrentSpeedGbps != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUNVLinkSpeedGbps,
+			prometheus.GaugeValue,
+			*m.CurrentSpeedGbps,
+			gpu,
+			m.Id,
+		)
+	}
+}
+
+func (mc *Collector) NewGPUNVLinkPortMetrics(ch chan<- prometheus.Metric, gpu, port string, m *PortMetrics) {
+	if m.TXBytes != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUNVLinkTransmitBytes,
+			prometheus.CounterValue,
+			*m.TXBytes,
+			gpu,
+			port,
+		)
+	}
+	if m.RXBytes != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUNVLinkReceiveBytes,
+			prometheus.CounterValue,
+			*m.RXBytes,
+			gpu,
+			port,
+		)
+	}
+	if m.Oem == nil || m.Oem.Nvidia == nil {
+		return
+	}
+
+	nvidia := m.Oem.Nvidia
+	if nvidia.ReplayCount != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUNVLinkReplayErrors,
+			prometheus.CounterValue,
+			float64(*nvidia.ReplayCount),
+			gpu,
+			port,
+		)
+	}
+	if nvidia.RecoveryCount != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUNVLinkRecoveryErrors,
+			prometheus.CounterValue,
+			float64(*nvidia.RecoveryCount),
+			gpu,
+			port,
+		)
+	}
+	if nvidia.FlitCRCCount != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUNVLinkCRCErrors,
+			prometheus.CounterValue,
+			float64(*nvidia.FlitCRCCount),
+			gpu,
+			port,
+			"flit",
+		)
+	}
+	if nvidia.DataCRCCount != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUNVLinkCRCErrors,
+			prometheus.CounterValue,
+			float64(*nvidia.DataCRCCount),
+			gpu,
+			port,
+			"data",
+		)
+	}
+}
+
+func (mc *Collector) NewChassisInletTemperatureCelsius(ch chan<- prometheus.Metric, v *float64) {
+	if v != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.ChassisInletTemperatureCelsius,
+			prometheus.GaugeValue,
+			*v,
+		)
+	}
+}
+
+func (mc *Collector) NewChassisExhaustTemperatureCelsius(ch chan<- prometheus.Metric, v *float64) {
+	if v != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.ChassisExhaustTemperatureCelsius,
+			prometheus.GaugeValue,
+			*v,
+		)
+	}
+}
+
+func (mc *Collector) NewChassisFanSpeedRPM(ch chan<- prometheus.Metric, v *float64, id, name string) {
+	if v != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.ChassisFanSpeedRPM,
+			prometheus.GaugeValue,
+			*v,
+			id,
+			strings.TrimSpace(name),
+		)
+	}
+}
+
+func (mc *Collector) NewChassisFanSpeedPercent(ch chan<- prometheus.Metric, v *float64, id, name string) {
+	if v != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.ChassisFanSpeedPercent,
+			prometheus.GaugeValue,
+			*v,
+			id,
+			strings.TrimSpace(name),
+		)
+	}
+}
+
+func (mc *Collector) NewChassisPSUInputPowerWatt(ch chan<- prometheus.Metric, v *float64, id string) {
+	if v != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.ChassisPSUInputPowerWatt,
+			prometheus.GaugeValue,
+			*v,
+			id,
+		)
+	}
+}
+
+func (mc *Collector) NewChassisPSUOutputPowerWatt(ch chan<- prometheus.Metric, v *float64, id string) {
+	if v != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.ChassisPSUOutputPowerWatt,
+			prometheus.GaugeValue,
+			*v,
+			id,
+		)
+	}
+}
+
+func (mc *Collector) NewChassisConsumedPowerWatt(ch chan<- prometheus.Metric, v *float64) {
+	if v != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.ChassisConsumedPowerWatt,
+			prometheus.GaugeValue,
+			*v,
+		)
+	}
+}
+
+func (mc *Collector) NewChassisPowerCap(ch chan<- prometheus.Metric, enabled bool, v *float64) {
+	ch <- prometheus.MustNewConstMetric(
+		mc.ChassisPowerCapEnabled,
+		prometheus.GaugeValue,
+		bool2value(enabled),
+	)
+	if enabled && v != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.ChassisPowerCapWatt,
+			prometheus.GaugeValue,
+			*v,
+		)
+	}
+}
+
+func (mc *Collector) NewGPUXGMILinks(ch chan<- prometheus.Metric, id string, m *AmdProcessorMetrics) {
+	for _, link := range m.XGMILinks {
+		if link.SpeedGbps != nil {
+			ch <- prometheus.MustNewConstMetric(
+				mc.GPUXGMISpeedGbps,
+				prometheus.GaugeValue,
+				*link.SpeedGbps,
+				id,
+				link.Id,
+			)
+		}
+		if link.ReadBytes != nil {
+			ch <- prometheus.MustNewConstMetric(
+				mc.GPUXGMIReadBytes,
+				prometheus.CounterValue,
+				*link.ReadBytes,
+				id,
+				link.Id,
+			)
+		}
+		if link.WriteBytes != nil {
+			ch <- prometheus.MustNewConstMetric(
+				mc.GPUXGMIWriteBytes,
+				prometheus.CounterValue,
+				*link.WriteBytes,
+				id,
+				link.Id,
+			)
+		}
+	}
+}
+
+func (mc *Collector) NewGPUMIGInfo(ch chan<- prometheus.Metric, gpu string, m *GPU) {
+	profile := m.Model
+	if profile == "" {
+		profile = m.Name
+	}
+	ch <- prometheus.MustNewConstMetric(
+		mc.GPUMIGInfo,
+		prometheus.UntypedValue,
+		1.0,
+		gpu,
+		m.Id,
+		strings.TrimSpace(profile),
+		strings.TrimSpace(m.UUID),
+	)
+	if m.MemorySummary.TotalMemorySizeMiB != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUMIGMemoryTotalBytes,
+			prometheus.GaugeValue,
+			*m.MemorySummary.TotalMemorySizeMiB*1024*1024,
+			gpu,
+			m.Id,
+		)
+	}
+}
+
+func (mc *Collector) NewGPUMIGMetrics(ch chan<- prometheus.Metric, gpu, instance string, m *GPUMetrics) {
+	if m.BandwidthPercent != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUMIGBandwidthPercent,
+			prometheus.GaugeValue,
+			*m.BandwidthPercent,
+			gpu,
+			instance,
+		)
+	}
+	if m.Oem != nil && m.Oem.Nvidia != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUMIGSMUtilizationPercent,
+			prometheus.GaugeValue,
+			float64(m.Oem.Nvidia.SMUtilizationPercent),
+			gpu,
+			instance,
+		)
+	}
+}
+
+func (mc *Collector) NewGPUPCIeLink(ch chan<- prometheus.Metric, id string, m *PCIeDevice) {
+	pcie := m.PCIeInterface
+	if pcie.LanesInUse != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUPCIeLinkWidth,
+			prometheus.GaugeValue,
+			float64(*pcie.LanesInUse),
+			id,
+		)
+	}
+	if pcie.MaxLanes != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUPCIeLinkMaxWidth,
+			prometheus.GaugeValue,
+			float64(*pcie.MaxLanes),
+			id,
+		)
+	}
+	if ok, value := pcieType2value(pcie.PCIeType); ok {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUPCIeLinkGeneration,
+			prometheus.GaugeValue,
+			float64(value),
+			id,
+		)
+	}
+	if ok, value := pcieType2value(pcie.MaxPCIeType); ok {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUPCIeLinkMaxGeneration,
+			prometheus.GaugeValue,
+			float64(value),
+			id,
+		)
+	}
+	if pcie.LanesInUse != nil && pcie.MaxLanes != nil {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUPCIeLinkDegraded,
+			prometheus.GaugeValue,
+			bool2value(*pcie.LanesInUse < *pcie.MaxLanes),
+			id,
+		)
+	}
+}
+
+func (mc *Collector) NewGPUTemperatureThresholds(ch chan<- prometheus.Metric, id string, thresholds map[string]float64) {
+	for name, v := range thresholds {
+		ch <- prometheus.MustNewConstMetric(
+			mc.GPUTemperatureThresholdCelsius,
+			prometheus.GaugeValue,
+			v,
+			id,
+			name,
+		)
+	}
+}
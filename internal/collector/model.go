@@ -122,18 +122,39 @@ type Processor struct {
 }
 
 type GPU struct {
+	OdataId               string  `json:"@odata.id"`
 	Id                    string  `json:"Id"`
 	Name                  string  `json:"Name"`
 	Description           string  `json:"Description"`
+	EnvironmentMetrics    Odata   `json:"EnvironmentMetrics"`
+	FirmwareVersion       string  `json:"FirmwareVersion"`
 	Manufacturer          string  `json:"Manufacturer"`
 	Model                 string  `json:"Model"`
 	PartNumber            string  `json:"PartNumber"`
+	SerialNumber          string  `json:"SerialNumber"`
+	UUID                  string  `json:"UUID"`
 	Metrics               Odata  `json:"Metrics"`
 	MemorySummary         struct {
         Metrics           Odata `json:"Metrics"`
+		TotalMemorySizeMiB *float64 `json:"TotalMemorySizeMiB"`
+		ECCModeEnabled     *bool    `json:"ECCModeEnabled"`
+		MemoryType         string   `json:"MemoryType"`
 	} `json:"MemorySummary"`
+	Links             struct {
+		PCIeDevice    Odata      `json:"PCIeDevice"`
+		PCIeFunctions OdataSlice `json:"PCIeFunctions"`
+	} `json:"Links"`
+	Ports             Odata   `json:"Ports"`
 	ProcessorType     string  `json:"ProcessorType"`
 	Status            Status  `json:"Status"`
+	SubProcessors     Odata   `json:"SubProcessors"`
+	TDPWatts          *float64 `json:"TDPWatts"`
+	Oem               struct {
+		Nvidia *struct {
+			SlowdownTemperatureCelsius *float64 `json:"SlowdownTemperatureCelsius"`
+			ShutdownTemperatureCelsius *float64 `json:"ShutdownTemperatureCelsius"`
+		} `json:"Nvidia"`
+	} `json:"Oem"`
 }
 
 type DellVideoMember struct {
@@ -155,6 +176,8 @@ type DellGPUSensorMember struct {
 	PowerBrakeStatus 	 string  `json:"PowerBrakeStatus"`
 	PrimaryGPUTemperatureCelsius float64 `json:"PrimaryGPUTemperatureCelsius"`
 	ThermalAlertStatus	 string  `json:"ThermalAlertStatus"`
+	CurrentPowerCapLimitMilliWatts *float64 `json:"CurrentPowerCapLimitMilliWatts"`
+	MaximumGPUPowerLimitMilliWatts *float64 `json:"MaximumGPUPowerLimitMilliWatts"`
 }
 
 type DellGPUSensors struct {
@@ -163,8 +186,8 @@ type DellGPUSensors struct {
 
 type GPUMetrics struct {
 	Id                    string  `json:"Id"`
-    TemperatureCelsius	  float64 `json:"TemperatureCelsius"`
-    ConsumedPowerWatt 	  float64 `json:"ConsumedPowerWatt"`
+    TemperatureCelsius	  *float64 `json:"TemperatureCelsius"`
+    ConsumedPowerWatt 	  *float64 `json:"ConsumedPowerWatt"`
     OperatingSpeedMHz	  *float64 `json:"OperatingSpeedMHz"`
     BandwidthPercent      *float64 `json:"BandwidthPercent"`
 	Oem				   *struct {
@@ -178,21 +201,225 @@ type GPUMetrics struct {
 			PCIeRawTxBandwidthGbps	   float64  `json:"PCIeRawTxBandwidthGbps"`
 			PCIeRawRxBandwidthGbps	   float64  `json:"PCIeRawRxBandwidthGbps"`
 		} `json:"Nvidia"`
+		Amd  *AmdProcessorMetrics `json:"Amd"`
+		Intel *IntelProcessorMetrics `json:"Intel"`
 		Dell *struct {
 			CurrentPCIeLinkSpeed     int     `json:"CurrentPCIeLinkSpeed"`
 			MaxSupportedPCIeLinkSpeed int     `json:"MaxSupportedPCIeLinkSpeed"`
 			DRAMUtilizationPercent    float64 `json:"DRAMUtilizationPercent"`
 		} `json:"Dell"`
 	} `json:"Oem"`
-	PCIeErrors *struct {
-		CorrectableErrorCount int `json:"CorrectableErrorCount"`
-	} `json:"PCIeErrors"`
+	PCIeErrors *PCIeErrors `json:"PCIeErrors"`
+
+	// Only read from the metric reports of the telemetry service
+	memoryTemperatureCelsius *float64
 }
  
 
+type PCIeErrors struct {
+	CorrectableErrorCount int  `json:"CorrectableErrorCount"`
+	FatalErrorCount       *int `json:"FatalErrorCount"`
+	NonFatalErrorCount    *int `json:"NonFatalErrorCount"`
+	L0ToRecoveryCount     *int `json:"L0ToRecoveryCount"`
+	ReplayCount           *int `json:"ReplayCount"`
+	ReplayRolloverCount   *int `json:"ReplayRolloverCount"`
+	NAKSentCount          *int `json:"NAKSentCount"`
+	NAKReceivedCount      *int `json:"NAKReceivedCount"`
+}
+
+// IntelProcessorMetrics holds the OEM extensions of the metrics of Intel
+// accelerators, such as Gaudi and Data Center GPU Max
+type IntelProcessorMetrics struct {
+	ComputeUtilizationPercent *float64 `json:"ComputeUtilizationPercent"`
+	MemoryUtilizationPercent  *float64 `json:"MemoryUtilizationPercent"`
+	HBMTemperatureCelsius     *float64 `json:"HBMTemperatureCelsius"`
+}
+
+// AmdProcessorMetrics holds the OEM extensions of the metrics of AMD Instinct GPUs
+type AmdProcessorMetrics struct {
+	GFXActivityPercent    *float64 `json:"GFXActivityPercent"`
+	UMCActivityPercent    *float64 `json:"UMCActivityPercent"`
+	HBMTemperatureCelsius *float64 `json:"HBMTemperatureCelsius"`
+	XGMILinks             []struct {
+		Id         string   `json:"Id"`
+		SpeedGbps  *float64 `json:"SpeedGbps"`
+		ReadBytes  *float64 `json:"ReadBytes"`
+		WriteBytes *float64 `json:"WriteBytes"`
+	} `json:"XGMILinks"`
+}
+
 type GPUMemoryMetrics struct {
     BandwidthPercent	  float64 `json:"BandwidthPercent"`
     OperatingSpeedMHz 	  float64 `json:"OperatingSpeedMHz"`
+	CurrentPeriod     *MemoryErrorCounts `json:"CurrentPeriod"`
+	LifeTime          *MemoryErrorCounts `json:"LifeTime"`
+	Oem               *struct {
+		Nvidia *NvidiaMemoryMetrics `json:"Nvidia"`
+	} `json:"Oem"`
+}
+
+type NvidiaMemoryMetrics struct {
+	RowRemappingFailed  *bool `json:"RowRemappingFailed"`
+	RowRemappingPending *bool `json:"RowRemappingPending"`
+	RowRemapping        *struct {
+		CorrectableRowRemappingCount   int `json:"CorrectableRowRemappingCount"`
+		UncorrectableRowRemappingCount int `json:"UncorrectableRowRemappingCount"`
+	} `json:"RowRemapping"`
+}
+
+// MemoryErrorCounts holds the ECC error counters of a memory device, either
+// for the lifetime of the device or since the last reset
+type MemoryErrorCounts struct {
+	CorrectableECCErrorCount   *int `json:"CorrectableECCErrorCount"`
+	UncorrectableECCErrorCount *int `json:"UncorrectableECCErrorCount"`
+}
+
+// SensorReading is the reading of an excerpt of a sensor, as embedded in
+// the metrics resources of newer Redfish versions
+type SensorReading struct {
+	Reading       *float64 `json:"Reading"`
+	DataSourceUri string   `json:"DataSourceUri"`
+}
+
+type Chassis struct {
+	Id                 string `json:"Id"`
+	ThermalSubsystem   Odata  `json:"ThermalSubsystem"`
+	PowerSubsystem     Odata  `json:"PowerSubsystem"`
+	EnvironmentMetrics Odata  `json:"EnvironmentMetrics"`
+	Thermal            Odata  `json:"Thermal"`
+	Power              Odata  `json:"Power"`
+	Sensors            Odata  `json:"Sensors"`
+}
+
+// Sensor is an entry of the sensor collection of a chassis
+type Sensor struct {
+	Id              string     `json:"Id"`
+	Name            string     `json:"Name"`
+	ReadingType     string     `json:"ReadingType"`
+	Reading         *float64   `json:"Reading"`
+	PhysicalContext string     `json:"PhysicalContext"`
+	RelatedItem     OdataSlice `json:"RelatedItem"`
+	Status          Status     `json:"Status"`
+	Thresholds      *struct {
+		UpperCaution  *SensorReading `json:"UpperCaution"`
+		UpperCritical *SensorReading `json:"UpperCritical"`
+		UpperFatal    *SensorReading `json:"UpperFatal"`
+	} `json:"Thresholds"`
+}
+
+type ThermalSubsystem struct {
+	ThermalMetrics Odata `json:"ThermalMetrics"`
+	Fans           Odata `json:"Fans"`
+}
+
+type ThermalMetrics struct {
+	TemperatureSummaryCelsius *struct {
+		Intake  *SensorReading `json:"Intake"`
+		Exhaust *SensorReading `json:"Exhaust"`
+	} `json:"TemperatureSummaryCelsius"`
+}
+
+type Fan struct {
+	Id           string `json:"Id"`
+	Name         string `json:"Name"`
+	SpeedPercent *struct {
+		Reading  *float64 `json:"Reading"`
+		SpeedRPM *float64 `json:"SpeedRPM"`
+	} `json:"SpeedPercent"`
+}
+
+type PowerSubsystem struct {
+	PowerSupplies Odata `json:"PowerSupplies"`
+}
+
+type PowerSupply struct {
+	Id      string `json:"Id"`
+	Name    string `json:"Name"`
+	Metrics Odata  `json:"Metrics"`
+}
+
+type PowerSupplyMetrics struct {
+	InputPowerWatts  *SensorReading `json:"InputPowerWatts"`
+	OutputPowerWatts *SensorReading `json:"OutputPowerWatts"`
+}
+
+type EnvironmentMetrics struct {
+	TemperatureCelsius *SensorReading `json:"TemperatureCelsius"`
+	PowerWatts      *SensorReading `json:"PowerWatts"`
+	PowerLimitWatts *struct {
+		SetPoint    *float64 `json:"SetPoint"`
+		ControlMode string   `json:"ControlMode"`
+	} `json:"PowerLimitWatts"`
+}
+
+// ThermalResponse is the legacy thermal resource of a chassis
+type ThermalResponse struct {
+	Temperatures []struct {
+		Name            string   `json:"Name"`
+		PhysicalContext string   `json:"PhysicalContext"`
+		ReadingCelsius  *float64 `json:"ReadingCelsius"`
+	} `json:"Temperatures"`
+	Fans []struct {
+		MemberId     string   `json:"MemberId"`
+		Name         string   `json:"Name"`
+		Reading      *float64 `json:"Reading"`
+		ReadingUnits string   `json:"ReadingUnits"`
+	} `json:"Fans"`
+}
+
+// PowerResponse is the legacy power resource of a chassis
+type PowerResponse struct {
+	PowerControl []struct {
+		PowerConsumedWatts *float64 `json:"PowerConsumedWatts"`
+		PowerLimit         *struct {
+			LimitInWatts *float64 `json:"LimitInWatts"`
+		} `json:"PowerLimit"`
+	} `json:"PowerControl"`
+	PowerSupplies []struct {
+		MemberId             string   `json:"MemberId"`
+		Name                 string   `json:"Name"`
+		PowerInputWatts      *float64 `json:"PowerInputWatts"`
+		PowerOutputWatts     *float64 `json:"PowerOutputWatts"`
+		LastPowerOutputWatts *float64 `json:"LastPowerOutputWatts"`
+	} `json:"PowerSupplies"`
+}
+
+type UpdateServiceResponse struct {
+	FirmwareInventory Odata `json:"FirmwareInventory"`
+}
+
+// SoftwareInventory is an entry of the firmware inventory of the update service
+type SoftwareInventory struct {
+	Id          string     `json:"Id"`
+	Name        string     `json:"Name"`
+	Version     string     `json:"Version"`
+	RelatedItem OdataSlice `json:"RelatedItem"`
+}
+
+// Port is a link of a processor, such as an NVLink port of a GPU
+type Port struct {
+	Id               string   `json:"Id"`
+	PortProtocol     string   `json:"PortProtocol"`
+	LinkStatus       string   `json:"LinkStatus"`
+	CurrentSpeedGbps *float64 `json:"CurrentSpeedGbps"`
+	Metrics          Odata    `json:"Metrics"`
+	Status           Status   `json:"Status"`
+}
+
+type PortMetrics struct {
+	Id      string   `json:"Id"`
+	RXBytes *float64 `json:"RXBytes"`
+	TXBytes *float64 `json:"TXBytes"`
+	Oem     *struct {
+		Nvidia *NvidiaPortMetrics `json:"Nvidia"`
+	} `json:"Oem"`
+}
+
+type NvidiaPortMetrics struct {
+	ReplayCount   *int `json:"ReplayCount"`
+	RecoveryCount *int `json:"RecoveryCount"`
+	FlitCRCCount  *int `json:"FlitCRCCount"`
+	DataCRCCount  *int `json:"DataCRCCount"`
 }
 
 type SystemResponse struct {
@@ -240,6 +467,9 @@ type SystemResponse struct {
 		Model                 string `json:"Model"`
 		Status                Status `json:"Status"`
 	} `json:"ProcessorSummary"`
+	Links          struct {
+		Chassis OdataSlice `json:"Chassis"`
+	} `json:"Links"`
 	Processors     Odata  `json:"Processors"`
 	SKU            string `json:"SKU"`
 	SecureBoot     Odata  `json:"SecureBoot"`
@@ -259,3 +489,122 @@ type SystemResponse struct {
 		} `json:"Hpe"`
 	} `json:"Oem"`
 }
+
+type PCIeDevice struct {
+	OdataId       string `json:"@odata.id"`
+	Id            string `json:"Id"`
+	Name          string `json:"Name"`
+	SerialNumber  string `json:"SerialNumber"`
+	PCIeInterface *struct {
+		PCIeType    string `json:"PCIeType"`
+		MaxPCIeType string `json:"MaxPCIeType"`
+		LanesInUse  *int   `json:"LanesInUse"`
+		MaxLanes    *int   `json:"MaxLanes"`
+	} `json:"PCIeInterface"`
+	Status Status `json:"Status"`
+}
+
+type TelemetryService struct {
+	ServiceEnabled *bool  `json:"ServiceEnabled"`
+	MetricReports  Odata  `json:"MetricReports"`
+	Status         Status `json:"Status"`
+}
+
+type MetricReport struct {
+	Id           string        `json:"Id"`
+	Timestamp    string        `json:"Timestamp"`
+	MetricValues []MetricValue `json:"MetricValues"`
+}
+
+type MetricValue struct {
+	MetricId       string `json:"MetricId"`
+	MetricProperty string `json:"MetricProperty"`
+	MetricValue    string `json:"MetricValue"`
+	Timestamp      string `json:"Timestamp"`
+	Oem            *struct {
+		Dell *struct {
+			ContextID string `json:"ContextID"`
+			FQDD      string `json:"FQDD"`
+			Label     string `json:"Label"`
+		} `json:"Dell"`
+	} `json:"Oem"`
+}
+
+type EventService struct {
+	ServiceEnabled     *bool  `json:"ServiceEnabled"`
+	ServerSentEventUri string `json:"ServerSentEventUri"`
+	Subscriptions      Odata  `json:"Subscriptions"`
+}
+
+type EventSubscription struct {
+	Destination     string `json:"Destination"`
+	Context         string `json:"Context"`
+	Protocol        string `json:"Protocol"`
+	EventFormatType string `json:"EventFormatType,omitempty"`
+}
+
+// EventMessage is either an event or a metric report, as sent by the event
+// service
+type EventMessage struct {
+	Id           string        `json:"Id"`
+	Context      string        `json:"Context"`
+	Timestamp    string        `json:"Timestamp"`
+	Events       []EventRecord `json:"Events"`
+	MetricValues []MetricValue `json:"MetricValues"`
+}
+
+type EventRecord struct {
+	EventId           string   `json:"EventId"`
+	EventTimestamp    string   `json:"EventTimestamp"`
+	MessageId         string   `json:"MessageId"`
+	Message           string   `json:"Message"`
+	MessageArgs       []string `json:"MessageArgs"`
+	MessageSeverity   string   `json:"MessageSeverity"`
+	Severity          string   `json:"Severity"`
+	OriginOfCondition Odata    `json:"OriginOfCondition"`
+}
+
+type Manager struct {
+	Id          string `json:"Id"`
+	LogServices Odata  `json:"LogServices"`
+}
+
+type LogService struct {
+	Id           string `json:"Id"`
+	LogEntryType string `json:"LogEntryType"`
+	Entries      Odata  `json:"Entries"`
+}
+
+// LogEntryCollection is a page of the entries of a log, which may link to
+// the next page
+type LogEntryCollection struct {
+	Members  []LogEntry `json:"Members"`
+	Count    *int       `json:"Members@odata.count"`
+	NextLink string     `json:"Members@odata.nextLink"`
+}
+
+type LogEntry struct {
+	Id          string   `json:"Id"`
+	Created     string   `json:"Created"`
+	EntryType   string   `json:"EntryType"`
+	Message     string   `json:"Message"`
+	MessageId   string   `json:"MessageId"`
+	MessageArgs []string `json:"MessageArgs"`
+	Severity    string   `json:"Severity"`
+	SensorType  string   `json:"SensorType"`
+	Links       struct {
+		OriginOfCondition Odata `json:"OriginOfCondition"`
+	} `json:"Links"`
+}
+
+// RedfishError is the error returned by the BMC for a failed request
+type RedfishError struct {
+	Error struct {
+		Code         string `json:"code"`
+		Message      string `json:"message"`
+		ExtendedInfo []struct {
+			MessageId string `json:"MessageId"`
+			Message   string `json:"Message"`
+		} `json:"@Message.ExtendedInfo"`
+	} `json:"error"`
+}
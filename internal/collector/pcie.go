@@ -0,0 +1,49 @@
+package collector
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// refreshPCIeLink emits the width and generation of the PCIe link of a GPU,
+// read from the PCIe device the GPU links to. When the GPU has no such link,
+// the PCIe devices of the system are searched for its serial number.
+func (client *Client) refreshPCIeLink(mc *Collector, ch chan<- prometheus.Metric, gpu *GPU) {
+	path := gpu.Links.PCIeDevice.OdataId
+	if path == "" {
+		path = client.findPCIeDevice(gpu.SerialNumber)
+	}
+	if path == "" {
+		return
+	}
+
+	device := PCIeDevice{}
+	if ok := client.redfish.Get(path, &device); !ok {
+		return
+	}
+
+	if device.PCIeInterface != nil {
+		mc.NewGPUPCIeLink(ch, gpu.Id, &device)
+	}
+}
+
+// findPCIeDevice returns the path of the PCIe device of the system with the
+// given serial number. The serial numbers of the devices are read once.
+func (client *Client) findPCIeDevice(serial string) string {
+	if serial == "" || len(client.pcieDevices) == 0 {
+		return ""
+	}
+
+	if client.pcieSerials == nil {
+		client.pcieSerials = make(map[string]string)
+		for _, c := range client.pcieDevices {
+			device := PCIeDevice{}
+			if ok := client.redfish.Get(c, &device); ok && device.SerialNumber != "" {
+				client.pcieSerials[strings.TrimSpace(device.SerialNumber)] = c
+			}
+		}
+	}
+
+	return client.pcieSerials[strings.TrimSpace(serial)]
+}
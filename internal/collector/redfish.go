@@ -2,6 +2,7 @@ package collector
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -18,6 +19,7 @@ import (
 
 type Redfish struct {
 	http     *http.Client
+	stream   *http.Client
 	baseurl  string
 	hostname string
 	username string
@@ -31,18 +33,38 @@ type Redfish struct {
 
 const redfishRootPath = "/redfish/v1"
 
+// withoutSession returns a copy of the Redfish host that uses basic
+// authentication and shares the http clients, so it can be used concurrently
+// with the original, which keeps refreshing its session.
+func (r *Redfish) withoutSession() *Redfish {
+	return &Redfish{
+		http:     r.http,
+		stream:   r.stream,
+		baseurl:  r.baseurl,
+		hostname: r.hostname,
+		username: r.username,
+		password: r.password,
+	}
+}
+
 func NewRedfish(scheme, hostname, username, password string) *Redfish {
+	transport := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+
 	return &Redfish{
 		baseurl:  fmt.Sprintf("%s://%s", scheme, hostname),
 		hostname: hostname,
 		username: username,
 		password: password,
 		http: &http.Client{
-			Transport: &http.Transport{
-				Proxy:           http.ProxyFromEnvironment,
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-			Timeout: time.Duration(config.Config.Timeout) * time.Second,
+			Transport: transport,
+			Timeout:   time.Duration(config.Config.Timeout) * time.Second,
+		},
+		// The event stream stays open, so it can not use the timeout
+		stream: &http.Client{
+			Transport: transport,
 		},
 	}
 }
@@ -207,14 +229,21 @@ func (r *Redfish) RefreshSession() bool {
 }
 
 func (r *Redfish) Get(path string, res any) bool {
+	return r.GetStatus(path, res, nil) == http.StatusOK
+}
+
+// GetStatus is like Get, but returns the status code of the response, or 0
+// when the request or decoding the response failed. The error returned by
+// the BMC is decoded into failure, if given.
+func (r *Redfish) GetStatus(path string, res any, failure *RedfishError) int {
 	if !strings.HasPrefix(path, redfishRootPath) {
-		return false
+		return 0
 	}
 
 	url := fmt.Sprintf("%s%s", r.baseurl, path)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return false
+		return 0
 	}
 
 	req.Header.Add("Accept", "application/json")
@@ -236,18 +265,24 @@ func (r *Redfish) Get(path string, res any) bool {
 	}
 	if err != nil {
 		log.Error("Failed to query %q: %v", url, err)
-		return false
+		return 0
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		log.Error("Unexpected status code from %q: %s", url, resp.Status)
-		return false
+		if failure != nil {
+			body, err := io.ReadAll(resp.Body)
+			if err == nil {
+				_ = json.Unmarshal(body, failure)
+			}
+		}
+		return resp.StatusCode
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Error("Error reading response from %q: %v", url, err)
-		return false
+		return 0
 	}
 
 	if config.Debug {
@@ -257,10 +292,10 @@ func (r *Redfish) Get(path string, res any) bool {
 	err = json.Unmarshal(body, res)
 	if err != nil {
 		log.Error("Error decoding response from %q: %v", url, err)
-		return false
+		return 0
 	}
 
-	return true
+	return http.StatusOK
 }
 
 func (r *Redfish) Exists(path string) bool {
@@ -298,3 +333,38 @@ func (r *Redfish) Exists(path string) bool {
 
 	return true
 }
+
+// EventRequest sends a request to the event service using basic
+// authentication. The caller must close the body of the response.
+func (r *Redfish) EventRequest(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	if !strings.HasPrefix(path, redfishRootPath) {
+		return nil, fmt.Errorf("invalid path %q", path)
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	url := fmt.Sprintf("%s%s", r.baseurl, path)
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Accept", "application/json, text/event-stream")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.SetBasicAuth(r.username, r.password)
+
+	log.Debug("Querying %q (%s)", url, method)
+	if method == "GET" {
+		return r.stream.Do(req)
+	}
+	return r.http.Do(req)
+}
@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+	"github.com/smc-public/idrac_gpu_exporter/internal/log"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// stateFile returns the path of the file holding the given kind of state for
+// a target, or an empty string if persistence is disabled.
+func stateFile(target, kind string) string {
+	if config.Config.StateDir == "" {
+		return ""
+	}
+
+	name := kind + "-" + unsafeFileChars.ReplaceAllString(target, "_") + ".json"
+	return filepath.Join(config.Config.StateDir, name)
+}
+
+// loadState decodes the JSON state file into v. A missing file is not an error.
+func loadState(path string, v any) {
+	if path == "" {
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	}
+	if err != nil {
+		log.Error("Failed to read state file %s: %v", path, err)
+		return
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		log.Error("Failed to decode state file %s: %v", path, err)
+	}
+}
+
+// saveState atomically replaces the JSON state file with the encoding of v.
+func saveState(path string, v any) {
+	if path == "" {
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Error("Failed to encode state file %s: %v", path, err)
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		log.Error("Failed to create state directory: %v", err)
+		return
+	}
+
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o640)
+	if err != nil {
+		log.Error("Failed to write state file %s: %v", tmp, err)
+		return
+	}
+
+	err = os.Rename(tmp, path)
+	if err != nil {
+		log.Error("Failed to replace state file %s: %v", path, err)
+	}
+}
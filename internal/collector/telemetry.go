@@ -0,0 +1,202 @@
+package collector
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/smc-public/idrac_gpu_exporter/internal/config"
+	"github.com/smc-public/idrac_gpu_exporter/internal/log"
+)
+
+// telemetryRetryInterval is the time to wait before reading the metric
+// reports again, after the telemetry service failed
+const telemetryRetryInterval = 10 * time.Minute
+
+// telemetryMetrics maps the metric ids, or the properties, of the values in
+// the metric reports onto the properties of the processor metrics. Other
+// values are reported as idrac_gpu_telemetry_value.
+var telemetryMetrics = map[string]string{
+	"PowerConsumption":   "ConsumedPowerWatt",
+	"ConsumedPowerWatt":  "ConsumedPowerWatt",
+	"PrimaryTemperature": "TemperatureCelsius",
+	"TemperatureCelsius": "TemperatureCelsius",
+	"MemoryTemperature":  "MemoryTemperature",
+	"BandwidthPercent":   "BandwidthPercent",
+	"OperatingSpeedMHz":  "OperatingSpeedMHz",
+}
+
+type telemetrySample struct {
+	id        string
+	report    string
+	metric    string
+	property  string
+	value     float64
+	timestamp time.Time
+}
+
+// RefreshTelemetry reads the configured metric reports of the telemetry
+// service. It returns the processor metrics of the GPUs found in the reports,
+// which replace reading the processor metrics of each GPU, and emits the other
+// values of the reports. The values are reported at the time of the scrape,
+// like the metrics derived from them. It returns nil when the
+// telemetry service is not available, in which case the processor metrics are
+// to be read instead. The telemetry service is tried again after a while,
+// unless it does not exist or is not licensed.
+func (client *Client) RefreshTelemetry(mc *Collector, ch chan<- prometheus.Metric) map[string]*GPUMetrics {
+	if client.telemetryPath == "" || time.Now().Before(client.telemetryRetry) {
+		return nil
+	}
+
+	service := TelemetryService{}
+	failure := RedfishError{}
+	status := client.redfish.GetStatus(client.telemetryPath, &service, &failure)
+	if status != http.StatusOK || service.MetricReports.OdataId == "" || (service.ServiceEnabled != nil && !*service.ServiceEnabled) {
+		client.disableTelemetry(status, &failure)
+		return nil
+	}
+
+	samples := map[string]*telemetrySample{}
+	found := false
+	for _, name := range config.Config.Telemetry.Reports {
+		report := MetricReport{}
+		failure = RedfishError{}
+		status = client.redfish.GetStatus(service.MetricReports.OdataId+"/"+name, &report, &failure)
+		if status != http.StatusOK {
+			continue
+		}
+		found = true
+
+		reportTime, _ := time.Parse(time.RFC3339, report.Timestamp)
+		for i := range report.MetricValues {
+			addTelemetrySample(samples, report.Id, &report.MetricValues[i], reportTime)
+		}
+	}
+
+	if !found {
+		client.disableTelemetry(status, &failure)
+		return nil
+	}
+
+	metrics := map[string]*GPUMetrics{}
+	for _, s := range samples {
+		if s.property == "" {
+			ch <- prometheus.MustNewConstMetric(mc.GPUTelemetryValue, prometheus.GaugeValue, s.value, s.id, s.report, s.metric)
+			continue
+		}
+
+		gpuMetrics, ok := metrics[s.id]
+		if !ok {
+			gpuMetrics = &GPUMetrics{Id: s.id}
+			metrics[s.id] = gpuMetrics
+		}
+		setTelemetryMetric(gpuMetrics, s.property, s.value)
+	}
+
+	return metrics
+}
+
+// disableTelemetry stops reading the metric reports for good when the
+// telemetry service or the reports do not exist or are not licensed, or else
+// until the retry interval has passed.
+func (client *Client) disableTelemetry(status int, failure *RedfishError) {
+	if status == http.StatusNotFound || telemetryNotLicensed(failure) {
+		log.Info("Telemetry service not available for %s, collecting from the processors", client.redfish.hostname)
+		client.telemetryPath = ""
+		return
+	}
+
+	log.Info("Telemetry service failed for %s, collecting from the processors for %v", client.redfish.hostname, telemetryRetryInterval)
+	client.telemetryRetry = time.Now().Add(telemetryRetryInterval)
+}
+
+// telemetryNotLicensed reports whether the request failed because the
+// telemetry service is not licensed
+func telemetryNotLicensed(failure *RedfishError) bool {
+	for _, e := range failure.Error.ExtendedInfo {
+		if strings.Contains(e.MessageId, "LIC") || strings.Contains(strings.ToLower(e.Message), "licens") {
+			return true
+		}
+	}
+	return strings.Contains(strings.ToLower(failure.Error.Message), "licens")
+}
+
+// addTelemetrySample adds the value to the samples, keeping only the latest
+// value of each series, since a report may hold several readings of a metric.
+func addTelemetrySample(samples map[string]*telemetrySample, report string, v *MetricValue, reportTime time.Time) {
+	id := telemetryGPU(v)
+	if id == "" {
+		return
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(v.MetricValue), 64)
+	if err != nil {
+		return
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, v.Timestamp)
+	if err != nil {
+		timestamp = reportTime
+	}
+
+	_, property, _ := strings.Cut(v.MetricProperty, "#/")
+	s := &telemetrySample{
+		id:        id,
+		report:    report,
+		metric:    v.MetricId,
+		value:     value,
+		timestamp: timestamp,
+	}
+	if p, ok := telemetryMetrics[v.MetricId]; ok {
+		s.property = p
+	} else if p, ok := telemetryMetrics[property]; ok {
+		s.property = p
+	}
+
+	key := s.key()
+	if prev, ok := samples[key]; ok && prev.timestamp.After(s.timestamp) {
+		return
+	}
+	samples[key] = s
+}
+
+// key identifies the series of the sample, where the values mapped onto the
+// same property of the processor metrics are one series.
+func (s *telemetrySample) key() string {
+	if s.property != "" {
+		return s.id + "\xff" + s.property
+	}
+	return s.id + "\xff" + s.report + "\xff" + s.metric
+}
+
+// setTelemetryMetric sets the property of the processor metrics
+func setTelemetryMetric(m *GPUMetrics, property string, v float64) {
+	switch property {
+	case "ConsumedPowerWatt":
+		m.ConsumedPowerWatt = &v
+	case "TemperatureCelsius":
+		m.TemperatureCelsius = &v
+	case "MemoryTemperature":
+		m.memoryTemperatureCelsius = &v
+	case "BandwidthPercent":
+		m.BandwidthPercent = &v
+	case "OperatingSpeedMHz":
+		m.OperatingSpeedMHz = &v
+	}
+}
+
+// telemetryGPU returns the id of the GPU a metric value belongs to, taken
+// from the processor in its property or from the Dell FQDD.
+func telemetryGPU(v *MetricValue) string {
+	if _, rest, ok := strings.Cut(v.MetricProperty, "/Processors/"); ok {
+		id, _, _ := strings.Cut(rest, "/")
+		id, _, _ = strings.Cut(id, "#")
+		return id
+	}
+	if v.Oem != nil && v.Oem.Dell != nil {
+		return v.Oem.Dell.FQDD
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRefreshTelemetryFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		disabled bool
+		retry    bool
+	}{
+		{"not found", http.StatusNotFound, "", true, false},
+		{"not licensed", http.StatusBadRequest, `{"error":{"@Message.ExtendedInfo":[{"MessageId":"IDRAC.2.9.LIC501","Message":"Unable to complete the operation because the required license is missing or expired."}]}}`, true, false},
+		{"server error", http.StatusInternalServerError, "", false, true},
+		{"unavailable", http.StatusServiceUnavailable, `{"error":{"message":"Service temporarily unavailable"}}`, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, mc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+			client.telemetryPath = "/redfish/v1/TelemetryService"
+
+			ch, wait := drain()
+			metrics := client.RefreshTelemetry(mc, ch)
+			wait()
+
+			if metrics != nil {
+				t.Fatalf("expected no metrics, got %v", metrics)
+			}
+			if disabled := client.telemetryPath == ""; disabled != tt.disabled {
+				t.Errorf("disabled = %v, want %v", disabled, tt.disabled)
+			}
+			if retry := client.telemetryRetry.After(time.Now()); retry != tt.retry {
+				t.Errorf("retry later = %v, want %v", retry, tt.retry)
+			}
+		})
+	}
+}
+
+func TestRefreshTelemetry(t *testing.T) {
+	client, mc := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/redfish/v1/TelemetryService":
+			_, _ = w.Write([]byte(`{"ServiceEnabled":true,"MetricReports":{"@odata.id":"/redfish/v1/TelemetryService/MetricReports"}}`))
+		case "/redfish/v1/TelemetryService/MetricReports/GPUMetrics":
+			_, _ = w.Write([]byte(`{"Id":"GPUMetrics","Timestamp":"2026-10-19T10:00:00Z","MetricValues":[
+				{"MetricId":"PowerConsumption","MetricValue":"410","Timestamp":"2026-10-19T09:59:50Z","Oem":{"Dell":{"FQDD":"Video.Slot.21-1"}}},
+				{"MetricId":"PowerConsumption","MetricValue":"415","Timestamp":"2026-10-19T10:00:00Z","Oem":{"Dell":{"FQDD":"Video.Slot.21-1"}}},
+				{"MetricId":"MemoryTemperature","MetricValue":"41","Oem":{"Dell":{"FQDD":"Video.Slot.21-1"}}},
+				{"MetricId":"GPUUtilization","MetricValue":"78","Oem":{"Dell":{"FQDD":"Video.Slot.21-1"}}},
+				{"MetricId":"PowerConsumption","MetricValue":"n/a","Oem":{"Dell":{"FQDD":"Video.Slot.22-1"}}}
+			]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	client.telemetryPath = "/redfish/v1/TelemetryService"
+
+	ch, wait := drain()
+	metrics := client.RefreshTelemetry(mc, ch)
+	values := wait()
+
+	gpu, ok := metrics["Video.Slot.21-1"]
+	if !ok || len(metrics) != 1 {
+		t.Fatalf("expected metrics of Video.Slot.21-1 only, got %v", metrics)
+	}
+	if gpu.ConsumedPowerWatt == nil || *gpu.ConsumedPowerWatt != 415 {
+		t.Errorf("ConsumedPowerWatt = %v, want the latest reading 415", gpu.ConsumedPowerWatt)
+	}
+	if v := memoryTemperature(gpu); v == nil || *v != 41 {
+		t.Errorf("memory temperature = %v, want 41", v)
+	}
+	if len(values) != 1 {
+		t.Errorf("expected 1 telemetry value, got %d", len(values))
+	}
+	for _, m := range values {
+		if _, _, metric := readMetric(t, m); metric.TimestampMs != nil {
+			t.Errorf("telemetry value has timestamp %d, want the time of the scrape", *metric.TimestampMs)
+		}
+	}
+}
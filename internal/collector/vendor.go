@@ -0,0 +1,381 @@
+package collector
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Sources of the GPU temperature. Only one temperature is reported per GPU,
+// the Dell OEM sensors take precedence over the DMTF processor metrics, which
+// take precedence over the sensors of the chassis.
+const (
+	TemperatureSourceDellOEM          = "dell_oem"
+	TemperatureSourceProcessorMetrics = "processor_metrics"
+	TemperatureSourceSensor           = "sensor"
+)
+
+// sensorDiscoveryInterval is the time after which the sensors of the GPUs are
+// looked up again, e.g. after a baseboard was replaced
+const sensorDiscoveryInterval = time.Hour
+
+// VendorAdapter maps the OEM extensions of a BMC vendor, or the plain DMTF
+// resources when there are none, onto the GPU state, health and temperature
+// series shared by all vendors.
+type VendorAdapter interface {
+	// Prepare reads the resources covering all GPUs, once per scrape.
+	Prepare(client *Client)
+	// Identify completes the identifiers of the GPU, e.g. its UUID.
+	Identify(gpu *GPU, info *GPUInfo)
+	// Status emits the state and health of the GPU.
+	Status(mc *Collector, ch chan<- prometheus.Metric, gpu *GPU)
+	// Sensors emits the sensors of the GPU, metrics is nil for GPUs that
+	// are not enabled or whose processor metrics could not be read.
+	Sensors(mc *Collector, ch chan<- prometheus.Metric, gpu *GPU, metrics *GPUMetrics)
+	// Thresholds adds the temperature thresholds of the GPU that are not
+	// already known from the DMTF resources.
+	Thresholds(gpu *GPU, thresholds map[string]float64)
+	// PowerLimits returns the configured power limit and the TDP of the GPU
+	// in watts from the OEM resources, nil when unknown.
+	PowerLimits(gpu *GPU) (limit, tdp *float64)
+}
+
+func NewVendorAdapter(vendor int) VendorAdapter {
+	switch vendor {
+	case DELL:
+		return &dellAdapter{}
+	case SUPERMICRO, NVIDIA:
+		return &supermicroAdapter{}
+	case HPE, LENOVO:
+		// HPE iLO and Lenovo XClarity Controller report the GPUs with the
+		// standard DMTF status and processor metrics, their OEM resources
+		// are not read
+		return &genericAdapter{}
+	default:
+		return &genericAdapter{}
+	}
+}
+
+// genericAdapter only uses the standard DMTF resources of the GPU.
+type genericAdapter struct{}
+
+func (a *genericAdapter) Prepare(client *Client) {}
+
+func (a *genericAdapter) Identify(gpu *GPU, info *GPUInfo) {}
+
+// Status maps the DMTF status of the GPU onto the values used by Dell
+func (a *genericAdapter) Status(mc *Collector, ch chan<- prometheus.Metric, gpu *GPU) {
+	switch gpu.Status.State {
+	case "":
+	case StateEnabled:
+		mc.NewGPUState(ch, gpu.Id, "Available")
+	case "Absent":
+		mc.NewGPUState(ch, gpu.Id, "NotApplicable")
+	default:
+		mc.NewGPUState(ch, gpu.Id, "Unavailable")
+	}
+
+	switch gpu.Status.Health {
+	case "OK", "Critical":
+		mc.NewGPUHealth(ch, gpu.Id, gpu.Status.Health)
+	case "Warning":
+		mc.NewGPUHealth(ch, gpu.Id, "Degraded")
+	case "":
+		if gpu.Status.State == StateEnabled {
+			mc.NewGPUHealth(ch, gpu.Id, "Unknown")
+		}
+	}
+}
+
+func (a *genericAdapter) Sensors(mc *Collector, ch chan<- prometheus.Metric, gpu *GPU, metrics *GPUMetrics) {
+	if metrics == nil {
+		return
+	}
+	if metrics.TemperatureCelsius != nil {
+		mc.NewPrimaryGPUTemperatureCelsius(ch, *metrics.TemperatureCelsius, gpu.Id, TemperatureSourceProcessorMetrics)
+	}
+	if v := memoryTemperature(metrics); v != nil {
+		mc.NewMemoryTemperatureCelsius(ch, *v, gpu.Id)
+	}
+}
+
+func (a *genericAdapter) Thresholds(gpu *GPU, thresholds map[string]float64) {}
+
+func (a *genericAdapter) PowerLimits(gpu *GPU) (*float64, *float64) {
+	return nil, nil
+}
+
+// memoryTemperature returns the memory temperature of the metric reports, or
+// the HBM temperature reported by AMD GPUs and Intel accelerators, if any
+func memoryTemperature(metrics *GPUMetrics) *float64 {
+	if metrics == nil {
+		return nil
+	}
+	if metrics.memoryTemperatureCelsius != nil {
+		return metrics.memoryTemperatureCelsius
+	}
+	if metrics.Oem == nil {
+		return nil
+	}
+	if metrics.Oem.Amd != nil {
+		return metrics.Oem.Amd.HBMTemperatureCelsius
+	}
+	if metrics.Oem.Intel != nil {
+		return metrics.Oem.Intel.HBMTemperatureCelsius
+	}
+	return nil
+}
+
+// dellAdapter uses the DellVideo and DellGPUSensors OEM resources of the
+// system, and falls back to the DMTF resources for GPUs missing from these.
+type dellAdapter struct {
+	genericAdapter
+	video   map[string]DellVideoMember
+	sensors map[string]DellGPUSensorMember
+}
+
+func (a *dellAdapter) Prepare(client *Client) {
+	a.video = map[string]DellVideoMember{}
+	a.sensors = map[string]DellGPUSensorMember{}
+
+	// Get dell video inventory
+
+	dellVideo := DellVideo{}
+	dellVideoPath := fmt.Sprintf("%s/Oem/Dell/DellVideo", client.systemPath)
+	if ok := client.redfish.Get(dellVideoPath, &dellVideo); ok {
+		for _, v := range dellVideo.Members {
+			a.video[v.Id] = v
+		}
+	}
+
+	// Get dell GPU sensor metrics
+
+	dellGPUSensors := DellGPUSensors{}
+	dellGPUSensorPath := fmt.Sprintf("%s/Oem/Dell/DellGPUSensors", client.systemPath)
+	if ok := client.redfish.Get(dellGPUSensorPath, &dellGPUSensors); ok {
+		for _, v := range dellGPUSensors.Members {
+			a.sensors[v.Id] = v
+		}
+	}
+}
+
+func (a *dellAdapter) Identify(gpu *GPU, info *GPUInfo) {
+	if v, ok := a.video[gpu.Id]; ok {
+		info.UUID = v.GPUGUID
+		info.SerialNumber = v.SerialNumber
+	}
+}
+
+func (a *dellAdapter) Status(mc *Collector, ch chan<- prometheus.Metric, gpu *GPU) {
+	v, ok := a.video[gpu.Id]
+	if !ok {
+		a.genericAdapter.Status(mc, ch, gpu)
+		return
+	}
+
+	mc.NewGPUState(ch, v.Id, v.GPUState)
+	mc.NewGPUHealth(ch, v.Id, v.GPUHealth)
+}
+
+func (a *dellAdapter) Sensors(mc *Collector, ch chan<- prometheus.Metric, gpu *GPU, metrics *GPUMetrics) {
+	v, ok := a.sensors[gpu.Id]
+	if !ok {
+		a.genericAdapter.Sensors(mc, ch, gpu, metrics)
+		return
+	}
+
+	mc.NewBoardPowerSupplyStatus(ch, &v)
+	mc.NewMemoryTemperatureCelsius(ch, v.MemoryTemperatureCelsius, v.Id)
+	mc.NewPowerBrakeStatus(ch, &v)
+	mc.NewPrimaryGPUTemperatureCelsius(ch, v.PrimaryGPUTemperatureCelsius, v.Id, TemperatureSourceDellOEM)
+	mc.NewThermalAlertStatus(ch, &v)
+}
+
+// PowerLimits returns the current power cap of the GPU, and its maximum power
+// limit as TDP, which DellGPUSensors reports in milliwatts.
+func (a *dellAdapter) PowerLimits(gpu *GPU) (*float64, *float64) {
+	v, ok := a.sensors[gpu.Id]
+	if !ok {
+		return nil, nil
+	}
+
+	return milliWatts(v.CurrentPowerCapLimitMilliWatts), milliWatts(v.MaximumGPUPowerLimitMilliWatts)
+}
+
+func milliWatts(v *float64) *float64 {
+	if v == nil || *v <= 0 {
+		return nil
+	}
+	w := *v / 1000
+	return &w
+}
+
+// supermicroAdapter is used for Supermicro BMCs and the HMC of NVIDIA HGX
+// baseboards, which report the GPU temperature, power and health as sensors
+// in the sensor collections of the chassis.
+type supermicroAdapter struct {
+	genericAdapter
+	links      []string
+	discovered time.Time
+	sensors    []Sensor
+}
+
+// Prepare reads the GPU sensors found by the last discovery, which is done
+// again once the discovery interval has passed, or after a sensor could not
+// be read.
+func (a *supermicroAdapter) Prepare(client *Client) {
+	if time.Since(a.discovered) >= sensorDiscoveryInterval {
+		a.discover(client)
+	}
+
+	a.sensors = nil
+	for _, s := range a.links {
+		sensor := Sensor{}
+		if ok := client.redfish.Get(s, &sensor); !ok {
+			a.discovered = time.Time{}
+			continue
+		}
+		a.sensors = append(a.sensors, sensor)
+	}
+}
+
+// discover looks up the GPU sensors in the sensor collections of all chassis.
+// Only sensors with "GPU" in their id are kept, since reading every sensor of
+// every chassis takes one request per sensor. The discovery is repeated by the
+// next scrape when a collection could not be read.
+func (a *supermicroAdapter) discover(client *Client) {
+	group := GroupResponse{}
+	if ok := client.redfish.Get(client.chassisGroup, &group); !ok {
+		return
+	}
+
+	complete := true
+	links := []string{}
+	for _, c := range group.Members.GetLinks() {
+		chassis := Chassis{}
+		if ok := client.redfish.Get(c, &chassis); !ok {
+			complete = false
+			continue
+		}
+		if chassis.Sensors.OdataId == "" {
+			continue
+		}
+
+		sensors := GroupResponse{}
+		if ok := client.redfish.Get(chassis.Sensors.OdataId, &sensors); !ok {
+			complete = false
+			continue
+		}
+
+		for _, s := range sensors.Members.GetLinks() {
+			if strings.Contains(strings.ToUpper(path.Base(s)), "GPU") {
+				links = append(links, s)
+			}
+		}
+	}
+
+	a.links = links
+	if complete {
+		a.discovered = time.Now()
+	}
+}
+
+func (a *supermicroAdapter) Sensors(mc *Collector, ch chan<- prometheus.Metric, gpu *GPU, metrics *GPUMetrics) {
+	a.genericAdapter.Sensors(mc, ch, gpu, metrics)
+	primary := metrics != nil && metrics.TemperatureCelsius != nil
+	memory := memoryTemperature(metrics) != nil
+
+	for _, s := range a.sensors {
+		if !sensorBelongsTo(&s, gpu) {
+			continue
+		}
+
+		mc.NewGPUSensorHealth(ch, gpu.Id, s.Id, s.Status.Health)
+
+		if s.Reading == nil {
+			continue
+		}
+
+		switch s.ReadingType {
+		case "Temperature":
+			mc.NewGPUSensorTemperatureCelsius(ch, *s.Reading, gpu.Id, s.Id)
+
+			// The first memory and other temperature sensors of the GPU
+			// are also reported as its memory and primary temperature
+			if isMemorySensor(&s) {
+				if !memory {
+					mc.NewMemoryTemperatureCelsius(ch, *s.Reading, gpu.Id)
+					memory = true
+				}
+			} else if !primary {
+				mc.NewPrimaryGPUTemperatureCelsius(ch, *s.Reading, gpu.Id, TemperatureSourceSensor)
+				primary = true
+			}
+		case "Power":
+			mc.NewGPUSensorPowerWatt(ch, *s.Reading, gpu.Id, s.Id)
+		}
+	}
+}
+
+// The thresholds are read from the first temperature sensor of the GPU that
+// is not a memory sensor, i.e. the sensor reported as primary temperature.
+func (a *supermicroAdapter) Thresholds(gpu *GPU, thresholds map[string]float64) {
+	for _, s := range a.sensors {
+		if s.ReadingType != "Temperature" || isMemorySensor(&s) || !sensorBelongsTo(&s, gpu) {
+			continue
+		}
+		addSensorThresholds(thresholds, &s)
+		return
+	}
+}
+
+// sensorBelongsTo reports whether the sensor lists the GPU as related item,
+// or whether its id or name starts with the id of the GPU, e.g. "GPU1 Temp"
+// for GPU1 or "HGX_GPU_SXM_1_TEMP_0" for GPU_SXM_1.
+func sensorBelongsTo(s *Sensor, gpu *GPU) bool {
+	for _, item := range s.RelatedItem {
+		if gpu.OdataId != "" && item.OdataId == gpu.OdataId {
+			return true
+		}
+	}
+
+	for _, name := range []string{s.Id, s.Name, strings.TrimPrefix(s.Id, "HGX_")} {
+		rest, ok := strings.CutPrefix(name, gpu.Id)
+		if ok && (rest == "" || !unicode.IsDigit(rune(rest[0]))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isMemorySensor(s *Sensor) bool {
+	if s.PhysicalContext == "Memory" {
+		return true
+	}
+
+	name := strings.ToUpper(s.Id + " " + s.Name)
+	return strings.Contains(name, "DRAM") || strings.Contains(name, "HBM") || strings.Contains(name, "MEM")
+}
+
+// addSensorThresholds adds the upper thresholds of the sensor that are not
+// already in thresholds
+func addSensorThresholds(thresholds map[string]float64, s *Sensor) {
+	if s.Thresholds == nil {
+		return
+	}
+
+	for name, t := range map[string]*SensorReading{
+		"upper_caution":  s.Thresholds.UpperCaution,
+		"upper_critical": s.Thresholds.UpperCritical,
+		"upper_fatal":    s.Thresholds.UpperFatal,
+	} {
+		if _, ok := thresholds[name]; !ok && t != nil && t.Reading != nil {
+			thresholds[name] = *t.Reading
+		}
+	}
+}
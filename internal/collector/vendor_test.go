@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSupermicroAdapterPrepare(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	gone := false
+
+	client, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path]++
+		missing := gone
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/redfish/v1/Chassis":
+			_, _ = w.Write([]byte(`{"Members":[{"@odata.id":"/redfish/v1/Chassis/1"}]}`))
+		case "/redfish/v1/Chassis/1":
+			_, _ = w.Write([]byte(`{"Sensors":{"@odata.id":"/redfish/v1/Chassis/1/Sensors"}}`))
+		case "/redfish/v1/Chassis/1/Sensors":
+			_, _ = w.Write([]byte(`{"Members":[
+				{"@odata.id":"/redfish/v1/Chassis/1/Sensors/GPU1_Temp"},
+				{"@odata.id":"/redfish/v1/Chassis/1/Sensors/GPU2_Temp"},
+				{"@odata.id":"/redfish/v1/Chassis/1/Sensors/CPU1_Temp"}
+			]}`))
+		case "/redfish/v1/Chassis/1/Sensors/GPU1_Temp":
+			_, _ = w.Write([]byte(`{"Id":"GPU1_Temp","Reading":41,"ReadingType":"Temperature"}`))
+		case "/redfish/v1/Chassis/1/Sensors/GPU2_Temp":
+			if missing {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"Id":"GPU2_Temp","Reading":43,"ReadingType":"Temperature"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	client.chassisGroup = "/redfish/v1/Chassis"
+
+	a := &supermicroAdapter{}
+	a.Prepare(client)
+	a.Prepare(client)
+
+	if len(a.sensors) != 2 {
+		t.Fatalf("read %d sensors, want 2", len(a.sensors))
+	}
+	for path, want := range map[string]int{
+		"/redfish/v1/Chassis":                     1,
+		"/redfish/v1/Chassis/1/Sensors":           1,
+		"/redfish/v1/Chassis/1/Sensors/GPU1_Temp": 2,
+		"/redfish/v1/Chassis/1/Sensors/CPU1_Temp": 0,
+	} {
+		if requests[path] != want {
+			t.Errorf("%s read %d times, want %d", path, requests[path], want)
+		}
+	}
+
+	// A sensor that can not be read makes the next scrape discover the
+	// sensors again
+	mu.Lock()
+	gone = true
+	mu.Unlock()
+	a.Prepare(client)
+	if len(a.sensors) != 1 || !a.discovered.IsZero() {
+		t.Fatalf("read %d sensors, discovered %v", len(a.sensors), a.discovered)
+	}
+	a.Prepare(client)
+	if requests["/redfish/v1/Chassis/1/Sensors"] != 2 {
+		t.Errorf("sensors discovered %d times, want 2", requests["/redfish/v1/Chassis/1/Sensors"])
+	}
+
+	// The sensors are discovered again once the interval has passed
+	a.discovered = time.Now().Add(-sensorDiscoveryInterval)
+	a.Prepare(client)
+	if requests["/redfish/v1/Chassis/1/Sensors"] != 3 {
+		t.Errorf("sensors discovered %d times, want 3", requests["/redfish/v1/Chassis/1/Sensors"])
+	}
+}
+
+func TestNewVendorAdapter(t *testing.T) {
+	tests := []struct {
+		name   string
+		vendor int
+		want   string
+	}{
+		{"dell", DELL, "*collector.dellAdapter"},
+		{"supermicro", SUPERMICRO, "*collector.supermicroAdapter"},
+		{"nvidia", NVIDIA, "*collector.supermicroAdapter"},
+		{"hpe", HPE, "*collector.genericAdapter"},
+		{"lenovo", LENOVO, "*collector.genericAdapter"},
+		{"unknown", UNKNOWN, "*collector.genericAdapter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprintf("%T", NewVendorAdapter(tt.vendor)); got != tt.want {
+				t.Errorf("NewVendorAdapter = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
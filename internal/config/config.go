@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/smc-public/idrac_gpu_exporter/internal/log"
 	"gopkg.in/yaml.v3"
@@ -11,6 +14,15 @@ import (
 var Debug bool = false
 var Config *RootConfig = nil
 
+var labelNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Labels added by the exporter itself, which can not be used as host labels.
+// The labels of the metrics are checked by the collector.
+var reservedLabels = []string{"id", "target", "version", "revision", "goversion"}
+
+// Processor types defined by Redfish, which can be selected for collection
+var processorTypes = []string{"CPU", "GPU", "FPGA", "DSP", "Accelerator", "Core", "Thread", "OEM"}
+
 func GetHostConfig(target string) *HostConfig {
 	Config.Mutex.Lock()
 	defer Config.Mutex.Unlock()
@@ -27,6 +39,7 @@ func GetHostConfig(target string) *HostConfig {
 			Scheme:   def.Scheme,
 			Username: def.Username,
 			Password: def.Password,
+			Implicit: true,
 		}
 		Config.Hosts[target] = host
 	}
@@ -34,6 +47,25 @@ func GetHostConfig(target string) *HostConfig {
 	return host
 }
 
+// GetTargets returns the sorted list of hosts that are explicitly listed in
+// the configuration, i.e. excluding "default" and hosts that were added
+// on demand using the default login information.
+func GetTargets() []string {
+	Config.Mutex.Lock()
+	defer Config.Mutex.Unlock()
+
+	targets := []string{}
+	for k, v := range Config.Hosts {
+		if k == "default" || v.Implicit {
+			continue
+		}
+		targets = append(targets, k)
+	}
+	sort.Strings(targets)
+
+	return targets
+}
+
 func NewConfig() *RootConfig {
 	return &RootConfig{
 		Hosts: make(map[string]*HostConfig),
@@ -82,10 +114,59 @@ func (c *RootConfig) Validate() error {
 		c.Timeout = 10
 	}
 
+	if c.MaxConcurrency == 0 {
+		c.MaxConcurrency = 10
+	}
+
 	if c.MetricsPrefix == "" {
 		c.MetricsPrefix = "idrac"
 	}
 
+	if len(c.ProcessorTypes) == 0 {
+		c.ProcessorTypes = []string{"GPU"}
+	}
+
+	for i, t := range c.ProcessorTypes {
+		valid := false
+		for _, p := range processorTypes {
+			if strings.EqualFold(t, p) {
+				c.ProcessorTypes[i] = p
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid processor type: %s", t)
+		}
+	}
+
+	// metrics section
+	if c.Metrics.All {
+		c.Metrics.Chassis = true
+		c.Metrics.Logs = true
+	}
+
+	// telemetry section
+	if len(c.Telemetry.Reports) == 0 {
+		c.Telemetry.Reports = []string{"GPUMetrics", "GPUStatistics"}
+	}
+
+	// events section
+	switch c.Events.Mode {
+	case "":
+		c.Events.Mode = "sse"
+	case "sse":
+	case "push":
+		if c.Events.Enabled && c.Events.PushURL == "" {
+			return fmt.Errorf("missing push_url for events mode: push")
+		}
+		if c.Events.Enabled && c.Events.PushToken == "" {
+			return fmt.Errorf("missing push_token for events mode: push")
+		}
+	default:
+		return fmt.Errorf("invalid events mode: %s", c.Events.Mode)
+	}
+
 	// hosts section
 	if len(c.Hosts) == 0 {
 		return fmt.Errorf("empty section: hosts")
@@ -110,6 +191,17 @@ func (c *RootConfig) Validate() error {
 			return fmt.Errorf("invalid scheme for host: %s", k)
 		}
 
+		for name := range v.Labels {
+			if !labelNameRegex.MatchString(name) || strings.HasPrefix(name, "__") {
+				return fmt.Errorf("invalid label name %q for host: %s", name, k)
+			}
+			for _, r := range reservedLabels {
+				if name == r {
+					return fmt.Errorf("reserved label name %q for host: %s", name, k)
+				}
+			}
+		}
+
 		v.Hostname = k
 	}
 
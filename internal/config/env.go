@@ -29,6 +29,22 @@ func getEnvBool(env string, val *bool) {
 	}
 }
 
+func getEnvList(env string, val *[]string) {
+	value := os.Getenv(env)
+	if len(value) == 0 {
+		return
+	}
+
+	list := []string{}
+	for _, s := range strings.Split(value, ",") {
+		s = strings.TrimSpace(s)
+		if len(s) > 0 {
+			list = append(list, s)
+		}
+	}
+	*val = list
+}
+
 func getEnvUint(env string, val *uint) {
 	s := os.Getenv(env)
 	if len(s) == 0 {
@@ -53,11 +69,23 @@ func (c *RootConfig) FromEnvironment() {
 	getEnvString("CONFIG_DEFAULT_SCHEME", &scheme)
 	getEnvString("CONFIG_TLS_CERT_FILE", &c.TLS.CertFile)
 	getEnvString("CONFIG_TLS_KEY_FILE", &c.TLS.KeyFile)
+	getEnvString("CONFIG_STATE_DIR", &c.StateDir)
+	getEnvString("CONFIG_EVENTS_MODE", &c.Events.Mode)
+	getEnvString("CONFIG_EVENTS_PUSH_URL", &c.Events.PushURL)
+	getEnvString("CONFIG_EVENTS_PUSH_TOKEN", &c.Events.PushToken)
+	getEnvList("CONFIG_PROCESSOR_TYPES", &c.ProcessorTypes)
+	getEnvList("CONFIG_TELEMETRY_REPORTS", &c.Telemetry.Reports)
 
 	getEnvUint("CONFIG_PORT", &c.Port)
 	getEnvUint("CONFIG_TIMEOUT", &c.Timeout)
+	getEnvUint("CONFIG_MAX_CONCURRENCY", &c.MaxConcurrency)
 
 	getEnvBool("CONFIG_TLS_ENABLED", &c.TLS.Enabled)
+	getEnvBool("CONFIG_METRICS_ALL", &c.Metrics.All)
+	getEnvBool("CONFIG_METRICS_CHASSIS", &c.Metrics.Chassis)
+	getEnvBool("CONFIG_METRICS_LOGS", &c.Metrics.Logs)
+	getEnvBool("CONFIG_TELEMETRY_ENABLED", &c.Telemetry.Enabled)
+	getEnvBool("CONFIG_EVENTS_ENABLED", &c.Events.Enabled)
 
 	def, ok := c.Hosts["default"]
 	if !ok {
@@ -3,10 +3,12 @@ package config
 import "sync"
 
 type HostConfig struct {
-	Username string `yaml:"username"`
-	Password string `yaml:"password"`
-	Scheme   string `yaml:"scheme"`
+	Username string            `yaml:"username"`
+	Password string            `yaml:"password"`
+	Scheme   string            `yaml:"scheme"`
+	Labels   map[string]string `yaml:"labels"`
 	Hostname string
+	Implicit bool `yaml:"-"`
 }
 
 type TLSConfig struct {
@@ -15,13 +17,43 @@ type TLSConfig struct {
 	KeyFile  string `yaml:"key_file"`
 }
 
+// MetricsConfig selects the optional metric groups collected in addition to the GPU metrics
+type MetricsConfig struct {
+	All     bool `yaml:"all"`
+	Chassis bool `yaml:"chassis"`
+	Logs    bool `yaml:"logs"`
+}
+
+// TelemetryConfig selects the metric reports of the telemetry service, which
+// are read instead of the processors when enabled
+type TelemetryConfig struct {
+	Enabled bool     `yaml:"enabled"`
+	Reports []string `yaml:"reports"`
+}
+
+// EventsConfig enables receiving the events of the hosts, either by streaming
+// them from the BMC (sse) or by subscribing push_url to them (push). The
+// pushed events are only accepted with push_token.
+type EventsConfig struct {
+	Enabled   bool   `yaml:"enabled"`
+	Mode      string `yaml:"mode"`
+	PushURL   string `yaml:"push_url"`
+	PushToken string `yaml:"push_token"`
+}
+
 type RootConfig struct {
-	Mutex         sync.Mutex
-	Address       string                 `yaml:"address"`
-	Port          uint                   `yaml:"port"`
-	HttpsProxy    string                 `yaml:"https_proxy"`
-	MetricsPrefix string                 `yaml:"metrics_prefix"`
-	TLS           TLSConfig              `yaml:"tls"`
-	Timeout       uint                   `yaml:"timeout"`
-	Hosts         map[string]*HostConfig `yaml:"hosts"`
+	Mutex          sync.Mutex
+	Address        string                 `yaml:"address"`
+	Port           uint                   `yaml:"port"`
+	HttpsProxy     string                 `yaml:"https_proxy"`
+	MetricsPrefix  string                 `yaml:"metrics_prefix"`
+	TLS            TLSConfig              `yaml:"tls"`
+	Timeout        uint                   `yaml:"timeout"`
+	MaxConcurrency uint                   `yaml:"max_concurrency"`
+	StateDir       string                 `yaml:"state_dir"`
+	ProcessorTypes []string               `yaml:"processor_types"`
+	Metrics        MetricsConfig          `yaml:"metrics"`
+	Telemetry      TelemetryConfig        `yaml:"telemetry"`
+	Events         EventsConfig           `yaml:"events"`
+	Hosts          map[string]*HostConfig `yaml:"hosts"`
 }